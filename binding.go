@@ -0,0 +1,319 @@
+package lightning
+
+import (
+	"encoding"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// valuesFunc returns the values of the request parameter with the given name.
+type valuesFunc func(name string) []string
+
+// Bind decodes the request body into v according to the request's Content-Type, then sets
+// the fields tagged with `path`, `query`, `header` and `cookie` if v points to a struct, like
+// BindQuery, and validates v once every source is bound, see Validate. The body is decoded as
+// follows:
+//
+//   - JSON, the default for bodies without a Content-Type, is decoded with the Config's JSONDecoder
+//   - XML is decoded with encoding/xml
+//   - URL-encoded and multipart forms set the fields tagged `form`, like BindQuery, and
+//     *multipart.FileHeader and []*multipart.FileHeader fields receive the uploaded files
+//
+// Empty bodies are not decoded. Errors are 400 Bad Request *HTTPErrors, and other content
+// types are rejected with 415 Unsupported Media Type. Multipart forms are parsed with
+// MultipartForm, and so with the UploadLimits of the route.
+func (c *Context) Bind(v any) error {
+	if err := c.bindBody(v); err != nil {
+		return err
+	}
+	if rv := reflect.Indirect(reflect.ValueOf(v)); rv.Kind() == reflect.Struct {
+		sources := []struct {
+			tag    string
+			values valuesFunc
+		}{
+			{"path", c.pathValues},
+			{"query", c.queryValues},
+			{"header", c.headerValues},
+			{"cookie", c.cookieValues},
+		}
+		for _, source := range sources {
+			if err := bindValues(rv, source.tag, source.values); err != nil {
+				return err
+			}
+		}
+	}
+	return c.Validate(v)
+}
+
+// BindQuery sets the fields of the struct v tagged with `query` to the query parameters
+// they name. For example
+//
+//	type ListRequest struct {
+//		Page  int       `query:"page" default:"1"`
+//		Tags  []string  `query:"tag"`
+//		Since time.Time `query:"since" time_format:"2006-01-02"`
+//	}
+//
+// Fields without a tag name use the field name, fields tagged "-" are skipped, and fields
+// of embedded structs are bound as well. Missing parameters leave their fields unchanged,
+// unless they have a `default` tag, whose value is split on commas for slices.
+//
+// Fields can be strings, booleans, numbers, time.Durations, time.Times, parsed as RFC 3339
+// or with the layout of their `time_format` tag, and types implementing
+// encoding.TextUnmarshaler, as well as pointers to them, which are allocated, and slices
+// of them, which receive every value of the parameter. Other fields receive its first value.
+//
+// Values that cannot be converted are 400 Bad Request *HTTPErrors naming the parameter.
+// v is not validated, as its other fields may be bound from other sources afterwards; call
+// Validate once they are, or use Bind, which binds every source.
+func (c *Context) BindQuery(v any) error {
+	return bindStruct(v, "query", c.queryValues)
+}
+
+// BindParams sets the fields of the struct v tagged with `path` to the URL parameters they
+// name. See BindQuery.
+func (c *Context) BindParams(v any) error {
+	return bindStruct(v, "path", c.pathValues)
+}
+
+// BindHeaders sets the fields of the struct v tagged with `header` to the request headers
+// they name. See BindQuery.
+func (c *Context) BindHeaders(v any) error {
+	return bindStruct(v, "header", c.headerValues)
+}
+
+// BindCookies sets the fields of the struct v tagged with `cookie` to the request cookies
+// they name. See BindQuery.
+func (c *Context) BindCookies(v any) error {
+	return bindStruct(v, "cookie", c.cookieValues)
+}
+
+// bindBody decodes the request body into v according to its Content-Type, see Bind.
+func (c *Context) bindBody(v any) error {
+	mediaType, _, _ := strings.Cut(c.ContentType(), ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	switch {
+	case mediaType == MIMEApplicationForm:
+		return bindStruct(v, "form", c.formValues)
+	case mediaType == MIMEMultipartForm:
+		form, err := c.MultipartForm()
+		if err != nil {
+			return err
+		}
+		err = bindStruct(v, "form", func(name string) []string {
+			return form.Value[name]
+		})
+		if err != nil {
+			return err
+		}
+		bindFiles(reflect.ValueOf(v).Elem(), form.File)
+		return nil
+	case len(c.RawBody()) == 0:
+		return nil
+	case mediaType == "" || mediaType == MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json"):
+		return c.JSONBody(v)
+	case mediaType == MIMEApplicationXML || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		if err := xml.Unmarshal(c.RawBody(), v); err != nil {
+			return NewHTTPError(StatusBadRequest).WithInternal(err)
+		}
+		return nil
+	}
+	return NewHTTPError(StatusUnsupportedMediaType)
+}
+
+// bindStruct binds the struct v points to with bindValues.
+func bindStruct(v any, tag string, values valuesFunc) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot bind %s parameters to %T, which is not a pointer to a struct", tag, v)
+	}
+	return bindValues(rv.Elem(), tag, values)
+}
+
+// bindValues sets the exported fields of the struct v that have the given tag, such as
+// `query:"page"`, to the values of the parameter the tag names, see Context.BindQuery.
+// It returns a 400 Bad Request *HTTPError if a value cannot be converted to its field's type.
+func bindValues(v reflect.Value, tag string, values valuesFunc) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup(tag)
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := bindValues(v.Field(i), tag, values); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		name, _, _ = strings.Cut(name, ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		vals := values(name)
+		if len(vals) == 0 {
+			def, ok := field.Tag.Lookup("default")
+			if !ok {
+				continue
+			}
+			vals = []string{def}
+			if isSlice(field.Type) {
+				vals = strings.Split(def, ",")
+			}
+		}
+		if err := setField(v.Field(i), vals, field.Tag.Get("time_format")); err != nil {
+			return NewHTTPError(StatusBadRequest, fmt.Sprintf("invalid %s parameter '%s'", tag, name)).WithInternal(err)
+		}
+	}
+	return nil
+}
+
+// setField sets the field to the values, allocating pointers and filling slices with
+// every value. Other fields are set to the first value. Times are parsed with the layout,
+// or as RFC 3339 if it is empty.
+func setField(field reflect.Value, values []string, layout string) error {
+	switch {
+	case field.Kind() == reflect.Pointer:
+		elem := reflect.New(field.Type().Elem())
+		if err := setField(elem.Elem(), values, layout); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	case isSlice(field.Type()):
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value, layout); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, values[0], layout)
+}
+
+// isSlice reports whether t is a slice type that is bound to every value of a parameter,
+// rather than one that unmarshals a single value, such as net.IP.
+func isSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// setValue parses the value according to the type of v, which must be addressable, and
+// stores it.
+func setValue(v reflect.Value, value string, layout string) error {
+	switch v.Type() {
+	case timeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// pathValues returns the value of the URL parameter with the given name.
+func (c *Context) pathValues(name string) []string {
+	for _, p := range c.params {
+		if p.Key == name {
+			return []string{p.Value}
+		}
+	}
+	return nil
+}
+
+// queryValues returns the values of the query parameter with the given name.
+func (c *Context) queryValues(name string) []string {
+	return byteStrings(c.ctx.QueryArgs().PeekMulti(name))
+}
+
+// headerValues returns the values of the request header with the given name.
+func (c *Context) headerValues(name string) []string {
+	return byteStrings(c.ctx.Request.Header.PeekAll(name))
+}
+
+// cookieValues returns the value of the request cookie with the given name.
+func (c *Context) cookieValues(name string) []string {
+	if cookie := c.ctx.Request.Header.Cookie(name); cookie != nil {
+		return []string{string(cookie)}
+	}
+	return nil
+}
+
+// formValues returns the values of the URL-encoded form field with the given name.
+func (c *Context) formValues(name string) []string {
+	return byteStrings(c.ctx.PostArgs().PeekMulti(name))
+}
+
+// byteStrings converts the byte slices to strings.
+func byteStrings(values [][]byte) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = string(value)
+	}
+	return strs
+}
@@ -0,0 +1,276 @@
+package lightning
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBindValues(t *testing.T) {
+	type Paging struct {
+		Page  int  `query:"page"`
+		Limit uint `query:"limit"`
+	}
+	type filter struct {
+		Paging
+		Name     string    `query:"name"`
+		Active   *bool     `query:"active"`
+		Scores   []float64 `query:"score"`
+		Sort     string    `query:""`
+		Ignored  string    `query:"-"`
+		Missing  string    `query:"missing"`
+		internal string    `query:"internal"`
+	}
+	values := map[string][]string{
+		"page":     {"2"},
+		"limit":    {"50"},
+		"name":     {"pen", "pencil"},
+		"active":   {"true"},
+		"score":    {"1.5", "2"},
+		"Sort":     {"price"},
+		"-":        {"value"},
+		"internal": {"value"},
+	}
+
+	f := filter{Missing: "unchanged"}
+	err := bindValues(reflect.ValueOf(&f).Elem(), "query", func(name string) []string {
+		return values[name]
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.Page != 2 || f.Limit != 50 {
+		t.Errorf("Expected the embedded struct to be bound, got %+v", f.Paging)
+	}
+	if f.Name != "pen" {
+		t.Errorf("Expected the first value 'pen', got '%s'", f.Name)
+	}
+	if f.Active == nil || !*f.Active {
+		t.Errorf("Expected a pointer to true, got %v", f.Active)
+	}
+	if !reflect.DeepEqual(f.Scores, []float64{1.5, 2}) {
+		t.Errorf("Expected scores [1.5 2], got %v", f.Scores)
+	}
+	if f.Sort != "price" {
+		t.Errorf("Expected an empty tag to use the field name, got '%s'", f.Sort)
+	}
+	if f.Ignored != "" || f.internal != "" {
+		t.Errorf("Expected skipped and unexported fields not to be bound, got '%s' and '%s'", f.Ignored, f.internal)
+	}
+	if f.Missing != "unchanged" {
+		t.Errorf("Expected missing parameters to leave fields unchanged, got '%s'", f.Missing)
+	}
+}
+
+func TestBindValuesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"int", &struct {
+			N int8 `header:"n"`
+		}{}},
+		{"bool", &struct {
+			B bool `header:"n"`
+		}{}},
+		{"unsupported", &struct {
+			M map[string]string `header:"n"`
+		}{}},
+	}
+	for _, tt := range tests {
+		err := bindValues(reflect.ValueOf(tt.v).Elem(), "header", func(name string) []string {
+			return []string{"300"}
+		})
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || httpErr.Status != StatusBadRequest {
+			t.Errorf("%s: Expected a 400 HTTPError, got %v", tt.name, err)
+			continue
+		}
+		if httpErr.Message != "invalid header parameter 'n'" || httpErr.Internal == nil {
+			t.Errorf("%s: Expected the parameter to be named and the cause kept, got %v", tt.name, httpErr)
+		}
+	}
+}
+
+type bindItem struct {
+	XMLName xml.Name `json:"-" xml:"item"`
+	Name    string   `json:"name" xml:"name" form:"name" validate:"required"`
+	Tags    []string `json:"tags" xml:"tag" form:"tag"`
+}
+
+func TestContext_Bind(t *testing.T) {
+	multipartBody := "--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"name\"\r\n\r\npen\r\n" +
+		"--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"tag\"\r\n\r\na\r\n" +
+		"--boundary\r\n" +
+		"Content-Disposition: form-data; name=\"tag\"\r\n\r\nb\r\n" +
+		"--boundary--\r\n"
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"json", MIMEApplicationJSONCharsetUTF8, `{"name":"pen","tags":["a","b"]}`, 0},
+		{"no content type", "", `{"name":"pen","tags":["a","b"]}`, 0},
+		{"vendor json", "application/vnd.acme.v2+json", `{"name":"pen","tags":["a","b"]}`, 0},
+		{"xml", MIMEApplicationXMLCharsetUTF8, `<item><name>pen</name><tag>a</tag><tag>b</tag></item>`, 0},
+		{"form", MIMEApplicationForm, `name=pen&tag=a&tag=b`, 0},
+		{"multipart", MIMEMultipartForm + "; boundary=boundary", multipartBody, 0},
+		{"malformed json", MIMEApplicationJSON, `{`, StatusBadRequest},
+		{"malformed xml", MIMEApplicationXML, `<item>`, StatusBadRequest},
+		{"invalid", MIMEApplicationJSON, `{"tags":["a"]}`, StatusBadRequest},
+		{"empty", MIMEApplicationJSON, ``, StatusBadRequest},
+		{"unsupported", MIMETextPlain, `pen`, StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		c, ctx := createTestContext(MethodPost, "/items", []byte(tt.body))
+		ctx.Request.Header.SetContentType(tt.contentType)
+
+		var item bindItem
+		err := c.Bind(&item)
+		if tt.status != 0 {
+			var httpErr *HTTPError
+			if !errors.As(err, &httpErr) || httpErr.Status != tt.status {
+				t.Errorf("%s: Expected a %d HTTPError, got %v", tt.name, tt.status, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Expected no error, got %v", tt.name, err)
+			continue
+		}
+		if item.Name != "pen" || !reflect.DeepEqual(item.Tags, []string{"a", "b"}) {
+			t.Errorf("%s: Expected name 'pen' and tags [a b], got %+v", tt.name, item)
+		}
+	}
+}
+
+type hexColor [3]byte
+
+func (h *hexColor) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "#%02x%02x%02x", &h[0], &h[1], &h[2])
+	return err
+}
+
+func TestContext_BindQuery(t *testing.T) {
+	type search struct {
+		Page    int           `query:"page" default:"1"`
+		Sizes   []int         `query:"size" default:"10,20"`
+		Since   time.Time     `query:"since" time_format:"2006-01-02"`
+		Until   *time.Time    `query:"until"`
+		Timeout time.Duration `query:"timeout"`
+		Color   hexColor      `query:"color"`
+		IP      net.IP        `query:"ip"`
+		Query   string        `query:"q" validate:"required"`
+	}
+
+	c, _ := createTestContext(MethodGet, "/search?q=pen&since=2024-05-01&until=2024-06-01T12:00:00Z&timeout=1m30s&color=%23ff8000&ip=10.0.0.1", nil)
+	var s search
+	if err := c.BindQuery(&s); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if s.Page != 1 || !reflect.DeepEqual(s.Sizes, []int{10, 20}) {
+		t.Errorf("Expected the defaults 1 and [10 20], got %d and %v", s.Page, s.Sizes)
+	}
+	if !s.Since.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected since 2024-05-01, got %v", s.Since)
+	}
+	if s.Until == nil || !s.Until.Equal(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected until 2024-06-01T12:00:00Z, got %v", s.Until)
+	}
+	if s.Timeout != 90*time.Second {
+		t.Errorf("Expected timeout 1m30s, got %v", s.Timeout)
+	}
+	if s.Color != (hexColor{0xff, 0x80, 0x00}) {
+		t.Errorf("Expected color #ff8000, got %v", s.Color)
+	}
+	if !s.IP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("Expected IP 10.0.0.1, got %v", s.IP)
+	}
+
+	c, _ = createTestContext(MethodGet, "/search?q=pen&since=yesterday", nil)
+	var httpErr *HTTPError
+	if err := c.BindQuery(&search{}); !errors.As(err, &httpErr) || httpErr.Message != "invalid query parameter 'since'" {
+		t.Errorf("Expected an invalid parameter error, got %v", err)
+	}
+	c, _ = createTestContext(MethodGet, "/search", nil)
+	s = search{}
+	if err := c.BindQuery(&s); err != nil {
+		t.Errorf("Expected BindQuery not to validate, got %v", err)
+	}
+	if err := c.Validate(&s); !errors.As(err, &httpErr) || httpErr.Status != StatusBadRequest {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	if err := c.BindQuery(search{}); err == nil || errors.As(err, &httpErr) {
+		t.Errorf("Expected an error for a non-pointer, got %v", err)
+	}
+}
+
+func TestContext_BindBodyAndQuery(t *testing.T) {
+	type createItem struct {
+		Name   string `json:"name" validate:"required"`
+		DryRun bool   `query:"dry_run" validate:"required"`
+	}
+
+	c, ctx := createTestContext(MethodPost, "/items?dry_run=true", []byte(`{"name":"pen"}`))
+	ctx.Request.Header.SetContentType(MIMEApplicationJSON)
+	var req createItem
+	if err := c.BindQuery(&req); err != nil {
+		t.Errorf("Expected BindQuery not to validate the body fields, got %v", err)
+	}
+	if err := c.Bind(&req); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if req != (createItem{Name: "pen", DryRun: true}) {
+		t.Errorf("Expected the body and query to be bound, got %+v", req)
+	}
+
+	c, ctx = createTestContext(MethodPost, "/items", []byte(`{"name":"pen"}`))
+	ctx.Request.Header.SetContentType(MIMEApplicationJSON)
+	var validationErr *ValidationError
+	if err := c.Bind(&createItem{}); !errors.As(err, &validationErr) || validationErr.Fields[0].Field != "DryRun" {
+		t.Errorf("Expected the missing query parameter to fail validation, got %v", err)
+	}
+}
+
+func TestContext_BindParamsHeadersCookies(t *testing.T) {
+	type request struct {
+		ID      int    `path:"id"`
+		Token   string `header:"X-Token"`
+		Session string `cookie:"session"`
+	}
+
+	app := NewApp()
+	var got request
+	app.Get("/items/:id", HandleErr(func(c *Context) error {
+		if err := c.BindParams(&got); err != nil {
+			return err
+		}
+		if err := c.BindHeaders(&got); err != nil {
+			return err
+		}
+		if err := c.BindCookies(&got); err != nil {
+			return err
+		}
+		c.Text(StatusOK, "ok")
+		return nil
+	}))
+
+	ctx := createFasthttpRequest(MethodGet, "/items/42")
+	ctx.Request.Header.Set("X-Token", "secret")
+	ctx.Request.Header.SetCookie("session", "abc")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusOK {
+		t.Errorf("Expected status %d, got %d", StatusOK, ctx.Response.StatusCode())
+	}
+	if got != (request{ID: 42, Token: "secret", Session: "abc"}) {
+		t.Errorf("Expected the parameters, header and cookie to be bound, got %+v", got)
+	}
+}
@@ -0,0 +1,150 @@
+package lightning
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParamConstraint reports whether the value of a URL parameter satisfies a constraint.
+// Constraints are referenced from route patterns by name, e.g. `/users/:id<int>`.
+type ParamConstraint func(value string) bool
+
+// defaultConstraints returns the constraints that are available in every route pattern.
+func defaultConstraints() map[string]ParamConstraint {
+	return map[string]ParamConstraint{
+		"int":   isInt,
+		"uint":  isUint,
+		"float": isFloat,
+		"bool":  isBool,
+		"alpha": isAlpha,
+		"alnum": isAlnum,
+		"uuid":  isUUID,
+	}
+}
+
+// splitParam splits a wildcard such as ":id<int>" or ":slug([a-z-]+)" into the parameter
+// name and its constraint, which is empty for unconstrained parameters.
+func splitParam(wildcard string) (name string, constraint string) {
+	end := 1 + nameLength(wildcard[1:])
+	return wildcard[1:end], wildcard[end:]
+}
+
+// compileConstraint returns the function matching values against the given constraint,
+// which is either the name of a registered constraint in angle brackets or a regular
+// expression in parentheses that must match the whole value.
+// It panics if the constraint is malformed or not registered.
+func compileConstraint(constraint string, constraints map[string]ParamConstraint) ParamConstraint {
+	switch {
+	case constraint == "":
+		return nil
+	case strings.HasPrefix(constraint, "<") && strings.HasSuffix(constraint, ">"):
+		name := constraint[1 : len(constraint)-1]
+		fn, ok := constraints[name]
+		if !ok {
+			panic(fmt.Sprintf("unknown parameter constraint '%s'", name))
+		}
+		return fn
+	case strings.HasPrefix(constraint, "(") && strings.HasSuffix(constraint, ")"):
+		re := regexp.MustCompile("^(?:" + constraint[1:len(constraint)-1] + ")$")
+		return re.MatchString
+	default:
+		panic(fmt.Sprintf("invalid parameter constraint '%s'", constraint))
+	}
+}
+
+// isInt reports whether s is a decimal integer with an optional sign.
+func isInt(s string) bool {
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	return isUint(s)
+}
+
+// isUint reports whether s is a non-empty string of decimal digits.
+func isUint(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isFloat reports whether s is a decimal number with an optional sign and fraction.
+func isFloat(s string) bool {
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	whole, fraction, found := strings.Cut(s, ".")
+	if !found {
+		return isUint(whole)
+	}
+	return (whole == "" || isUint(whole)) && (fraction == "" || isUint(fraction)) && whole+fraction != ""
+}
+
+// isBool reports whether s is a boolean as accepted by strconv.ParseBool.
+func isBool(s string) bool {
+	switch s {
+	case "1", "t", "T", "TRUE", "true", "True", "0", "f", "F", "FALSE", "false", "False":
+		return true
+	}
+	return false
+}
+
+// isAlpha reports whether s is a non-empty string of ASCII letters.
+func isAlpha(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isLetter(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAlnum reports whether s is a non-empty string of ASCII letters and digits.
+func isAlnum(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isLetter(s[i]) && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// isUUID reports whether s is a UUID in its canonical 8-4-4-4-12 hexadecimal form.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(s[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isHex(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'a' && b <= 'f') || (b >= 'A' && b <= 'F')
+}
@@ -0,0 +1,79 @@
+package lightning
+
+import (
+	"testing"
+)
+
+func TestSplitParam(t *testing.T) {
+	tests := []struct {
+		segment    string
+		name       string
+		constraint string
+	}{
+		{":id", "id", ""},
+		{":id<int>", "id", "<int>"},
+		{":slug([a-z0-9-]+)", "slug", "([a-z0-9-]+)"},
+	}
+
+	for _, tt := range tests {
+		name, constraint := splitParam(tt.segment)
+		if name != tt.name || constraint != tt.constraint {
+			t.Errorf("splitParam(%q) = %q, %q, want %q, %q", tt.segment, name, constraint, tt.name, tt.constraint)
+		}
+	}
+}
+
+func TestCompileConstraint(t *testing.T) {
+	constraints := defaultConstraints()
+
+	if compileConstraint("", constraints) != nil {
+		t.Error("Expected nil constraint for unconstrained parameter")
+	}
+
+	re := compileConstraint("([a-z]+)", constraints)
+	if !re("abc") || re("abc1") || re("") {
+		t.Error("Expected regular expression constraint to match the whole value")
+	}
+
+	for _, constraint := range []string{"<unknown>", "[a-z]+", "<int"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for constraint %q", constraint)
+				}
+			}()
+			compileConstraint(constraint, constraints)
+		}()
+	}
+}
+
+func TestDefaultConstraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		valid   []string
+		invalid []string
+	}{
+		{"int", []string{"0", "42", "-7", "+3"}, []string{"", "-", "4.2", "abc"}},
+		{"uint", []string{"0", "42"}, []string{"", "-7", "4a"}},
+		{"float", []string{"1", "1.5", "-0.5", ".5", "5."}, []string{"", ".", "1.2.3", "1e3"}},
+		{"bool", []string{"true", "False", "1", "0"}, []string{"", "yes", "2"}},
+		{"alpha", []string{"abc", "ABC"}, []string{"", "ab1", "a-b"}},
+		{"alnum", []string{"abc1", "A2"}, []string{"", "a-1", "a b"}},
+		{"uuid", []string{"123e4567-e89b-12d3-a456-426614174000"}, []string{"", "123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g"}},
+	}
+
+	constraints := defaultConstraints()
+	for _, tt := range tests {
+		fn := constraints[tt.name]
+		for _, v := range tt.valid {
+			if !fn(v) {
+				t.Errorf("Expected %q to satisfy <%s>", v, tt.name)
+			}
+		}
+		for _, v := range tt.invalid {
+			if fn(v) {
+				t.Errorf("Expected %q not to satisfy <%s>", v, tt.name)
+			}
+		}
+	}
+}
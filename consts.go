@@ -9,6 +9,7 @@ const (
 	MIMEApplicationXMLCharsetUTF8  = "application/xml; charset=utf-8"
 	MIMEApplicationJSONCharsetUTF8 = "application/json; charset=utf-8"
 	MIMEMultipartForm              = "multipart/form-data"
+	MIMEApplicationForm            = "application/x-www-form-urlencoded"
 	MIMEOctetStream                = "application/octet-stream"
 )
 
@@ -21,6 +22,7 @@ const (
 	HeaderAccept             = "Accept"
 	HeaderAcceptEncoding     = "Accept-Encoding"
 	HeaderAcceptLanguage     = "Accept-Language"
+	HeaderAllow              = "Allow"
 	HeaderAuthorization      = "Authorization"
 	HeaderCacheControl       = "Cache-Control"
 	HeaderConnection         = "Connection"
@@ -34,6 +36,10 @@ const (
 	HeaderXForwardedFor      = "X-Forwarded-For"
 	HeaderLocation           = "Location"
 	HeaderUpgrade            = "Upgrade"
+	HeaderVary               = "Vary"
+	HeaderAcceptVersion      = "Accept-Version"
+	HeaderDeprecation        = "Deprecation"
+	HeaderSunset             = "Sunset"
 )
 
 // HTTP status codes
@@ -51,6 +57,7 @@ const (
 	StatusNotModified                  = 304
 	StatusUseProxy                     = 305
 	StatusTemporaryRedirect            = 307
+	StatusPermanentRedirect            = 308
 	StatusBadRequest                   = 400
 	StatusUnauthorized                 = 401
 	StatusPaymentRequired              = 402
@@ -90,4 +97,7 @@ const (
 	MethodConnect = "CONNECT"
 	MethodOptions = "OPTIONS"
 	MethodTrace   = "TRACE"
+
+	// MethodAny registers a route for every method, see Application.Any.
+	MethodAny = "*"
 )
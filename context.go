@@ -1,21 +1,22 @@
 package lightning
 
 import (
+	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io/fs"
+	"math"
+	"mime/multipart"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/valyala/fasthttp"
 )
 
-// use a single instance of Validate, it caches struct info
-var validate = validator.New()
-
 // Context represents the context of an HTTP request/response.
 type Context struct {
 	App      *Application
@@ -23,22 +24,41 @@ type Context struct {
 	req      *request
 	res      *response
 	data     contextData
+	params   Params
+	route    *Route
 	handlers []HandlerFunc
 	index    int
 	Method   string
 	Path     string
+
+	// stdCtx is the context.Context of the request, created by Context with cancel, and
+	// cancelled early by the connection watch stopped with stopWatch.
+	stdCtx    context.Context
+	cancel    context.CancelCauseFunc
+	stopWatch func()
+
+	// multipartForm is the form parsed by MultipartForm, whose files are removed by reset.
+	multipartForm *multipart.Form
+
+	// mountPrefix is the part of the path routed to the Application that mounted c.App.
+	mountPrefix string
 }
 
 func (c *Context) reset() {
+	c.releaseStdContext()
+	c.releaseMultipartForm()
 	c.App = nil
 	c.ctx = nil
 	c.req = nil
 	c.res = nil
-	c.data = nil
+	clear(c.data)
+	c.params = c.params[:0]
+	c.route = nil
 	c.handlers = nil
 	c.index = -1
 	c.Method = ""
 	c.Path = ""
+	c.mountPrefix = ""
 }
 
 // NewContext creates a new Context object for the given fasthttp request context.
@@ -60,7 +80,7 @@ func (c *Context) setHandlers(handlers []HandlerFunc) {
 }
 
 // setParams sets the URL parameters for the request.
-func (c *Context) setParams(params map[string]string) {
+func (c *Context) setParams(params Params) {
 	c.req.setParams(params)
 }
 
@@ -69,10 +89,20 @@ func (c *Context) setApp(app *Application) {
 	c.App = app
 }
 
+// Route returns the route matched by the request, with its pattern, name and metadata.
+// It returns nil if no route matched, e.g. in the NotFoundHandler.
+func (c *Context) Route() *Route {
+	return c.route
+}
+
 // SkipFlush sets the skipFlush flag to true, which prevents the response buffer from being flushed.
 func (c *Context) SkipFlush() {}
 
-// Next calls the next middleware function in the chain.
+// abortIndex is the index of the handler chain of an aborted Context. It is far beyond the
+// length of any chain, so that Next no longer calls handlers.
+const abortIndex = math.MaxInt / 2
+
+// Next calls the next middleware function in the chain, unless the chain was aborted.
 func (c *Context) Next() {
 	c.index++
 	if c.index < len(c.handlers) {
@@ -80,6 +110,31 @@ func (c *Context) Next() {
 	}
 }
 
+// Abort prevents the remaining handlers of the chain from being called, even by handlers
+// calling Next after it. The handlers already running, such as the middlewares that called
+// Next, resume as usual and can check IsAborted.
+func (c *Context) Abort() {
+	c.index = abortIndex
+}
+
+// IsAborted reports whether the handler chain was aborted.
+func (c *Context) IsAborted() bool {
+	return c.index >= abortIndex
+}
+
+// AbortWithStatus sets the status code of the response and aborts the handler chain.
+func (c *Context) AbortWithStatus(code int) {
+	c.SetStatus(code)
+	c.Abort()
+}
+
+// AbortWithJSON writes a JSON response with the given status code and object, and aborts
+// the handler chain.
+func (c *Context) AbortWithJSON(code int, obj any) {
+	c.JSON(code, obj)
+	c.Abort()
+}
+
 // RawBody returns the raw request body.
 func (c *Context) RawBody() []byte {
 	return c.req.body()
@@ -92,6 +147,7 @@ func (c *Context) StringBody() string {
 
 // JSONBody parses the request body as JSON and stores the result in v.
 // If valid is true, the struct is validated after parsing.
+// Errors are returned as a 400 Bad Request *HTTPError wrapping the decoding or validation error.
 func (c *Context) JSONBody(v any, valid ...bool) error {
 	decode := json.Unmarshal
 	if c.App != nil && c.App.Config.JSONDecoder != nil {
@@ -100,10 +156,10 @@ func (c *Context) JSONBody(v any, valid ...bool) error {
 
 	err := decode(c.RawBody(), v)
 	if err != nil {
-		return err
+		return NewHTTPError(StatusBadRequest).WithInternal(err)
 	}
 	if len(valid) > 0 && valid[0] {
-		return validate.Struct(v)
+		return c.Validate(v)
 	}
 	return nil
 }
@@ -388,18 +444,24 @@ func (c *Context) XML(code int, obj any) {
 }
 
 // File writes a file as the response.
+// If the file does not exist, it returns a 404 Not Found *HTTPError wrapping the cause.
 // WARNING: The caller MUST validate that the path does not contain user-controlled
 // input that could lead to directory traversal. Use FileFromSafeDir for safer file serving.
 func (c *Context) File(filepath string) error {
-	return c.res.file(filepath)
+	err := c.res.file(filepath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NewHTTPError(StatusNotFound).WithInternal(err)
+	}
+	return err
 }
 
 // FileFromSafeDir serves a file from the specified safe directory, preventing path traversal.
-// It validates that the resolved file path stays within safeDir.
+// It validates that the resolved file path stays within safeDir, and returns a 403 Forbidden
+// *HTTPError otherwise.
 func (c *Context) FileFromSafeDir(safeDir string, requestPath string) error {
 	cleanPath := filepath.Clean(requestPath)
 	if strings.Contains(cleanPath, "..") {
-		return fmt.Errorf("invalid file path: path traversal detected")
+		return NewHTTPError(StatusForbidden).WithInternal(fmt.Errorf("invalid file path: path traversal detected"))
 	}
 
 	absSafeDir, err := filepath.Abs(safeDir)
@@ -409,13 +471,14 @@ func (c *Context) FileFromSafeDir(safeDir string, requestPath string) error {
 
 	fullPath := filepath.Clean(filepath.Join(absSafeDir, cleanPath))
 	if !strings.HasPrefix(fullPath, absSafeDir+string(os.PathSeparator)) && fullPath != absSafeDir {
-		return fmt.Errorf("access denied: path escapes safe directory")
+		return NewHTTPError(StatusForbidden).WithInternal(fmt.Errorf("access denied: path escapes safe directory"))
 	}
 
 	return c.File(fullPath)
 }
 
 // GetData returns the value of a custom data field for the context.
+// The generic GetData function returns it with its type, and supports typed keys.
 func (c *Context) GetData(key string) any {
 	return c.data.get(key)
 }
@@ -430,6 +493,12 @@ func (c *Context) DelData(key string) {
 	c.data.del(key)
 }
 
+// URLFor builds the URL of the route registered under the given name.
+// See Application.URL for how params are used.
+func (c *Context) URLFor(name string, params ...any) (string, error) {
+	return c.App.URL(name, params...)
+}
+
 // Redirect redirects the request to a new URL with the given status code.
 // WARNING: Do not pass user-controlled input directly. Use RedirectSafe instead.
 func (c *Context) Redirect(code int, url string) {
@@ -437,10 +506,10 @@ func (c *Context) Redirect(code int, url string) {
 }
 
 // RedirectSafe performs a redirect only if the URL is a safe relative path
-// or belongs to one of the allowed hosts.
+// or belongs to one of the allowed hosts, and returns a 400 Bad Request *HTTPError otherwise.
 func (c *Context) RedirectSafe(code int, url string, allowedHosts ...string) error {
 	if !isSafeRedirectURL(url, allowedHosts...) {
-		return fmt.Errorf("unsafe redirect URL: %s", url)
+		return NewHTTPError(StatusBadRequest).WithInternal(fmt.Errorf("unsafe redirect URL: %s", url))
 	}
 	c.res.redirect(code, url)
 	return nil
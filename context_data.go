@@ -1,19 +1,81 @@
 package lightning
 
-// contextData is a map[string]interface{} that can be used to store data in the context of a request.
-type contextData map[string]interface{}
+import "fmt"
+
+// contextData is a map that can be used to store data in the context of a request, keyed by
+// strings or by typed keys created with NewKey.
+type contextData map[any]any
 
 // get retrieves the value associated with the given key from the contextData.
-func (c contextData) get(key string) interface{} {
+func (c contextData) get(key any) any {
 	return c[key]
 }
 
 // set sets the value associated with the given key in the contextData.
-func (c contextData) set(key string, value interface{}) {
+func (c contextData) set(key any, value any) {
 	c[key] = value
 }
 
 // del deletes the value associated with the given key from the contextData.
-func (c contextData) del(key string) {
+func (c contextData) del(key any) {
 	delete(c, key)
 }
+
+// Key is a typed key for the data of a Context, created with NewKey. Keys never collide
+// with string keys or with other keys, even of the same name.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns a new key for data of type T. The name is only used to describe the key.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the name of the key.
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+// DataKey is the type of the keys of data of type T: strings, as used by Context.GetData,
+// or typed keys.
+type DataKey[T any] interface {
+	~string | *Key[T]
+}
+
+// GetData returns the data of the Context stored under the given key, and whether it
+// exists and is of type T, e.g.
+//
+//	user, ok := lightning.GetData[*User](c, "user")
+func GetData[T any, K DataKey[T]](c *Context, key K) (T, bool) {
+	value, ok := c.data.get(key).(T)
+	return value, ok
+}
+
+// MustGetData returns the data of the Context stored under the given key, like GetData,
+// but panics if it does not exist or is not of type T.
+func MustGetData[T any, K DataKey[T]](c *Context, key K) T {
+	value, ok := c.data[key]
+	if !ok {
+		panic(fmt.Sprintf("context data '%v' does not exist", key))
+	}
+	v, ok := value.(T)
+	if !ok {
+		panic(fmt.Sprintf("context data '%v' is a %T, not a %T", key, value, v))
+	}
+	return v
+}
+
+// SetData stores the value in the data of the Context under the given key, e.g.
+//
+//	var userKey = lightning.NewKey[*User]("user")
+//	lightning.SetData(c, userKey, user)
+func SetData[T any, K DataKey[T]](c *Context, key K, value T) {
+	c.data.set(key, value)
+}
+
+// DelData deletes the data of the Context stored under the given typed key. Data stored
+// under string keys is deleted with Context.DelData.
+func DelData[T any](c *Context, key *Key[T]) {
+	c.data.del(key)
+}
@@ -109,3 +109,86 @@ func TestContextData_Set(t *testing.T) {
 		})
 	}
 }
+
+type testUser struct {
+	Name string
+}
+
+func TestGetData(t *testing.T) {
+	c, _ := createTestContext("GET", "/test", nil)
+	userKey := NewKey[*testUser]("user")
+	otherKey := NewKey[*testUser]("user")
+
+	SetData(c, userKey, &testUser{Name: "alice"})
+	c.SetData("user", "bob")
+	c.SetData("count", 3)
+
+	if user, ok := GetData[*testUser](c, userKey); !ok || user.Name != "alice" {
+		t.Errorf("Expected user 'alice', got %v", user)
+	}
+	if _, ok := GetData[*testUser](c, otherKey); ok {
+		t.Error("Expected keys of the same name not to collide")
+	}
+	if name, ok := GetData[string](c, "user"); !ok || name != "bob" {
+		t.Errorf("Expected 'bob' for the string key, got '%s'", name)
+	}
+	if _, ok := GetData[string](c, "count"); ok {
+		t.Error("Expected no value of another type")
+	}
+	if count := MustGetData[int](c, "count"); count != 3 {
+		t.Errorf("Expected 3, got %d", count)
+	}
+	if userKey.String() != "user" {
+		t.Errorf("Expected key name 'user', got '%s'", userKey.String())
+	}
+
+	DelData(c, userKey)
+	if _, ok := GetData[*testUser](c, userKey); ok {
+		t.Error("Expected the value to be deleted")
+	}
+	if c.GetData("user") != "bob" {
+		t.Error("Expected the string key to be kept")
+	}
+}
+
+func TestMustGetDataPanics(t *testing.T) {
+	c, _ := createTestContext("GET", "/test", nil)
+	c.SetData("count", "three")
+
+	tests := map[string]string{
+		"missing": "context data 'missing' does not exist",
+		"count":   "context data 'count' is a string, not a int",
+	}
+	for key, want := range tests {
+		func() {
+			defer func() {
+				if r := recover(); r != want {
+					t.Errorf("Expected panic '%s', got %v", want, r)
+				}
+			}()
+			MustGetData[int](c, key)
+		}()
+	}
+}
+
+func TestContextDataReused(t *testing.T) {
+	app := NewApp()
+	userKey := NewKey[string]("user")
+	app.Get("/users", func(c *Context) {
+		if _, ok := GetData[string](c, userKey); ok {
+			t.Error("Expected no data from a previous request")
+		}
+		SetData(c, userKey, "alice")
+	})
+
+	for i := 0; i < 3; i++ {
+		app.serveRequest(createFasthttpRequest(MethodGet, "/users"))
+	}
+
+	c := app.acquireContext(createFasthttpRequest(MethodGet, "/users"))
+	data := reflect.ValueOf(c.data).Pointer()
+	app.releaseContext(c)
+	if len(c.data) != 0 || reflect.ValueOf(c.data).Pointer() != data {
+		t.Error("Expected the data map to be cleared and kept for reuse")
+	}
+}
@@ -0,0 +1,64 @@
+package lightning
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrClientDisconnected is the cause of the cancellation of Context.Context when the
+	// client closes the connection before the response is sent.
+	ErrClientDisconnected = errors.New("client disconnected")
+	// ErrServerShutdown is the cause of the cancellation of Context.Context when the
+	// Application shuts down.
+	ErrServerShutdown = errors.New("server shutdown")
+)
+
+// Context returns the context.Context of the request, to be passed to databases and
+// downstream services. It is cancelled when the client closes the connection, where the
+// connection supports watching for it, when the Application shuts down, and when the
+// handler chain returns. context.Cause reports ErrClientDisconnected and ErrServerShutdown
+// respectively. Unlike the Context itself, it can be used by goroutines outliving the request.
+//
+// Middleware can replace it with WithContext, e.g. to set a deadline or store values.
+func (c *Context) Context() context.Context {
+	if c.stdCtx == nil {
+		parent := context.Background()
+		if c.req != nil && c.req.app != nil {
+			parent = c.req.app.baseCtx
+		}
+		ctx, cancel := context.WithCancelCause(parent)
+		c.stdCtx, c.cancel = ctx, cancel
+		if c.ctx != nil && c.ctx.Conn() != nil {
+			c.stopWatch = watchDisconnect(c.ctx.Conn(), func() {
+				cancel(ErrClientDisconnected)
+			})
+		}
+	}
+	return c.stdCtx
+}
+
+// WithContext replaces the context returned by Context for the rest of the handler chain.
+// It should be derived from Context, so that it is cancelled with the request, e.g.
+//
+//	ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+//	defer cancel()
+//	c.WithContext(ctx)
+//	c.Next()
+func (c *Context) WithContext(ctx context.Context) {
+	if c.stdCtx == nil {
+		c.Context()
+	}
+	c.stdCtx = ctx
+}
+
+// releaseStdContext stops watching the connection and cancels the context of the request.
+func (c *Context) releaseStdContext() {
+	if c.stopWatch != nil {
+		c.stopWatch()
+	}
+	if c.cancel != nil {
+		c.cancel(context.Canceled)
+	}
+	c.stdCtx, c.cancel, c.stopWatch = nil, nil, nil
+}
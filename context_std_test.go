@@ -0,0 +1,59 @@
+package lightning
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type contextKey string
+
+func TestContext_Context(t *testing.T) {
+	app := NewApp()
+	var requestCtx context.Context
+	app.Use(func(c *Context) {
+		ctx, cancel := context.WithTimeout(c.Context(), time.Minute)
+		defer cancel()
+		c.WithContext(context.WithValue(ctx, contextKey("user"), "alice"))
+		c.Next()
+	})
+	app.Get("/users", func(c *Context) {
+		requestCtx = c.Context()
+		if c.Context() != requestCtx {
+			t.Error("Expected Context to return the same context")
+		}
+		if _, ok := requestCtx.Deadline(); !ok {
+			t.Error("Expected the deadline set by the middleware")
+		}
+		c.Text(StatusOK, requestCtx.Value(contextKey("user")).(string))
+	})
+
+	ctx := createFasthttpRequest(MethodGet, "/users")
+	app.serveRequest(ctx)
+	if body := string(ctx.Response.Body()); body != "alice" {
+		t.Errorf("Expected body 'alice', got '%s'", body)
+	}
+	if requestCtx.Err() == nil {
+		t.Error("Expected the context to be cancelled once the handler chain returned")
+	}
+	if requestCtx.Value(contextKey("user")) != "alice" {
+		t.Error("Expected the values of the context to survive the request")
+	}
+}
+
+func TestContext_ContextShutdown(t *testing.T) {
+	app := NewApp()
+	var cause error
+	app.Get("/slow", func(c *Context) {
+		ctx := c.Context()
+		app.Shutdown()
+		<-ctx.Done()
+		cause = context.Cause(ctx)
+	})
+
+	app.serveRequest(createFasthttpRequest(MethodGet, "/slow"))
+	if !errors.Is(cause, ErrServerShutdown) {
+		t.Errorf("Expected cause %v, got %v", ErrServerShutdown, cause)
+	}
+}
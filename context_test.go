@@ -216,7 +216,7 @@ func TestSetHandlers(t *testing.T) {
 
 func TestContext_Param(t *testing.T) {
 	c, _ := createTestContext("GET", "/users/123", nil)
-	params := map[string]string{"id": "123"}
+	params := Params{{Key: "id", Value: "123"}}
 	c.setParams(params)
 
 	got := c.Param("id")
@@ -226,14 +226,27 @@ func TestContext_Param(t *testing.T) {
 	}
 
 	gotParams := c.Params()
-	if !reflect.DeepEqual(gotParams, params) {
+	if !reflect.DeepEqual(gotParams, map[string]string{"id": "123"}) {
 		t.Errorf("ctx.Params() = %v, want %v", gotParams, params)
 	}
 }
 
+func TestContext_ParamHyphenatedName(t *testing.T) {
+	app := NewApp()
+	var got string
+	app.Get("/users/:user-id", func(c *Context) {
+		got = c.Param("user-id")
+	})
+
+	app.serveRequest(createFasthttpRequest(MethodGet, "/users/42"))
+	if got != "42" {
+		t.Errorf("ctx.Param(\"user-id\") = %q, want %q", got, "42")
+	}
+}
+
 func TestContext_ParamInt(t *testing.T) {
 	c, _ := createTestContext("GET", "/users/123", nil)
-	params := map[string]string{"id": "123"}
+	params := Params{{Key: "id", Value: "123"}}
 	c.setParams(params)
 
 	got, err := c.ParamInt("id")
@@ -248,7 +261,7 @@ func TestContext_ParamInt(t *testing.T) {
 
 func TestContext_ParamIntWithException(t *testing.T) {
 	c, _ := createTestContext("GET", "/users/abc", nil)
-	params := map[string]string{"id": "abc"}
+	params := Params{{Key: "id", Value: "abc"}}
 	c.setParams(params)
 
 	_, err := c.ParamInt("id")
@@ -259,7 +272,7 @@ func TestContext_ParamIntWithException(t *testing.T) {
 
 func TestContext_ParamInt64(t *testing.T) {
 	c, _ := createTestContext("GET", "/users/123", nil)
-	params := map[string]string{"id": "123"}
+	params := Params{{Key: "id", Value: "123"}}
 	c.setParams(params)
 
 	got, err := c.ParamInt64("id")
@@ -274,7 +287,7 @@ func TestContext_ParamInt64(t *testing.T) {
 
 func TestContext_ParamUInt(t *testing.T) {
 	c, _ := createTestContext("GET", "/users/123", nil)
-	params := map[string]string{"id": "123"}
+	params := Params{{Key: "id", Value: "123"}}
 	c.setParams(params)
 
 	got, err := c.ParamUInt("id")
@@ -289,7 +302,7 @@ func TestContext_ParamUInt(t *testing.T) {
 
 func TestContext_ParamUInt64(t *testing.T) {
 	c, _ := createTestContext("GET", "/users/123", nil)
-	params := map[string]string{"id": "123"}
+	params := Params{{Key: "id", Value: "123"}}
 	c.setParams(params)
 
 	got, err := c.ParamUInt64("id")
@@ -304,7 +317,7 @@ func TestContext_ParamUInt64(t *testing.T) {
 
 func TestContext_ParamFloat32(t *testing.T) {
 	c, _ := createTestContext("GET", "/users/123.456", nil)
-	params := map[string]string{"id": "123.456"}
+	params := Params{{Key: "id", Value: "123.456"}}
 	c.setParams(params)
 
 	got, err := c.ParamFloat32("id")
@@ -319,7 +332,7 @@ func TestContext_ParamFloat32(t *testing.T) {
 
 func TestContext_ParamFloat64(t *testing.T) {
 	c, _ := createTestContext("GET", "/users/123.456", nil)
-	params := map[string]string{"id": "123.456"}
+	params := Params{{Key: "id", Value: "123.456"}}
 	c.setParams(params)
 
 	got, err := c.ParamFloat64("id")
@@ -1658,7 +1671,7 @@ func TestContext_QueryFloat64Empty(t *testing.T) {
 func TestContext_setParams(t *testing.T) {
 	c, _ := createTestContext("GET", "/test", nil)
 
-	params := map[string]string{"id": "123", "name": "test"}
+	params := Params{{Key: "id", Value: "123"}, {Key: "name", Value: "test"}}
 	c.setParams(params)
 
 	if c.Param("id") != "123" {
@@ -1706,6 +1719,74 @@ func TestContext_NextNoMoreHandlers(t *testing.T) {
 	}
 }
 
+func TestContext_Abort(t *testing.T) {
+	order := []string{}
+	aborted := false
+	ctx := &Context{
+		handlers: []HandlerFunc{
+			func(c *Context) {
+				c.Next()
+				aborted = c.IsAborted()
+			},
+			func(c *Context) {
+				order = append(order, "auth")
+				c.Abort()
+				c.Next()
+			},
+			func(c *Context) {
+				order = append(order, "handler")
+			},
+		},
+		index: -1,
+	}
+
+	ctx.Next()
+
+	if !reflect.DeepEqual(order, []string{"auth"}) {
+		t.Errorf("Expected only the aborting handler to run, got %v", order)
+	}
+	if !aborted {
+		t.Error("Expected the outer middleware to see the abort")
+	}
+}
+
+func TestContext_AbortWithStatusAndJSON(t *testing.T) {
+	app := NewApp()
+	app.Use(func(c *Context) {
+		switch c.Header(HeaderAuthorization) {
+		case "":
+			c.AbortWithStatus(StatusUnauthorized)
+		case "expired":
+			c.AbortWithJSON(StatusForbidden, Map{"message": "token expired"})
+		}
+		c.Next()
+	})
+	app.Get("/secret", func(c *Context) {
+		c.Text(StatusOK, "secret")
+	})
+
+	tests := []struct {
+		auth   string
+		status int
+		body   string
+	}{
+		{"", StatusUnauthorized, ""},
+		{"expired", StatusForbidden, `{"message":"token expired"}`},
+		{"valid", StatusOK, "secret"},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodGet, "/secret")
+		ctx.Request.Header.Set(HeaderAuthorization, tt.auth)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("Expected status %d for '%s', got %d", tt.status, tt.auth, ctx.Response.StatusCode())
+		}
+		if body := string(ctx.Response.Body()); body != tt.body {
+			t.Errorf("Expected body '%s' for '%s', got '%s'", tt.body, tt.auth, body)
+		}
+	}
+}
+
 func TestContext_StringBodyEmpty(t *testing.T) {
 	c, _ := createTestContext("POST", "/test", nil)
 
@@ -1968,3 +2049,29 @@ func TestContext_FileExists(t *testing.T) {
 		t.Errorf("File() returned error: %v", err)
 	}
 }
+
+func TestContext_Route(t *testing.T) {
+	app := NewApp()
+	var matched *Route
+	app.Use(func(c *Context) {
+		matched = c.Route()
+		c.Next()
+	})
+	app.Get("/users/:id", func(c *Context) {}).With(WithName("user"), WithMeta("scope", "users:read"))
+
+	app.serveRequest(createFasthttpRequest(MethodGet, "/users/1"))
+	if matched == nil {
+		t.Fatal("Expected the matched route in middleware")
+	}
+	if matched.Pattern() != "/users/:id" || matched.Name() != "user" {
+		t.Errorf("Unexpected route '%s' named '%s'", matched.Pattern(), matched.Name())
+	}
+	if scope, _ := matched.Meta("scope"); scope != "users:read" {
+		t.Errorf("Expected scope 'users:read', got %v", scope)
+	}
+
+	app.serveRequest(createFasthttpRequest(MethodGet, "/missing"))
+	if matched != nil {
+		t.Errorf("Expected no route for a miss, got '%s'", matched.Pattern())
+	}
+}
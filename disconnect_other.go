@@ -0,0 +1,10 @@
+//go:build !unix
+
+package lightning
+
+import "net"
+
+// watchDisconnect does not watch connections on this platform, see disconnect_unix.go.
+func watchDisconnect(conn net.Conn, cancel func()) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,52 @@
+//go:build unix
+
+package lightning
+
+import (
+	"crypto/tls"
+	"net"
+	"syscall"
+	"time"
+)
+
+// watchDisconnect calls cancel when the peer closes conn, until the returned function is
+// called. The connection is watched by peeking at it, so that data sent after the request,
+// such as a pipelined request, is not consumed: such data ends the watch instead.
+// Connections that do not expose their file descriptor are not watched.
+func watchDisconnect(conn net.Conn, cancel func()) (stop func()) {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+	sc, ok := conn.(syscall.Conn)
+	if !ok {
+		return func() {}
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var buf [1]byte
+		// Read waits for the connection to be readable whenever the callback returns false,
+		// and returns early once the read deadline is set by stop.
+		raw.Read(func(fd uintptr) bool {
+			n, _, err := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK)
+			if err == syscall.EAGAIN || err == syscall.EINTR {
+				return false
+			}
+			if err != nil || n == 0 {
+				cancel()
+			}
+			return true
+		})
+	}()
+
+	return func() {
+		conn.SetReadDeadline(time.Unix(1, 0))
+		<-done
+		conn.SetReadDeadline(time.Time{})
+	}
+}
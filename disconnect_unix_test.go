@@ -0,0 +1,82 @@
+//go:build unix
+
+package lightning
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+func serveTCP(t *testing.T, app *Application) net.Conn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &fasthttp.Server{Handler: app.RequestHandler()}
+	go server.Serve(ln)
+	t.Cleanup(func() { server.Shutdown() })
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	return conn
+}
+
+func TestContext_ContextClientDisconnect(t *testing.T) {
+	app := NewApp()
+	started := make(chan struct{})
+	cause := make(chan error, 1)
+	app.Get("/slow", func(c *Context) {
+		ctx := c.Context()
+		close(started)
+		select {
+		case <-ctx.Done():
+			cause <- context.Cause(ctx)
+		case <-time.After(5 * time.Second):
+			cause <- nil
+		}
+	})
+
+	conn := serveTCP(t, app)
+	conn.Write([]byte("GET /slow HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+	<-started
+	conn.Close()
+
+	if err := <-cause; !errors.Is(err, ErrClientDisconnected) {
+		t.Errorf("Expected cause %v, got %v", ErrClientDisconnected, err)
+	}
+}
+
+func TestContext_ContextKeepAlive(t *testing.T) {
+	app := NewApp()
+	app.Get("/ping", func(c *Context) {
+		if c.Context().Err() != nil {
+			t.Error("Expected the context not to be cancelled")
+		}
+		c.Text(StatusOK, "pong")
+	})
+
+	conn := serveTCP(t, app)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for i := 0; i < 3; i++ {
+		conn.Write([]byte("GET /ping HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		res, err := http.ReadResponse(reader, nil)
+		if err != nil {
+			t.Fatalf("Failed to read response %d: %v", i, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != StatusOK {
+			t.Errorf("Expected status %d, got %d", StatusOK, res.StatusCode)
+		}
+	}
+}
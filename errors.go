@@ -0,0 +1,113 @@
+package lightning
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ErrHandlerFunc is a handler that returns an error instead of writing an error response
+// itself. It is registered as a HandlerFunc with HandleErr.
+type ErrHandlerFunc func(*Context) error
+
+// HandleErr returns a HandlerFunc running h, which can be registered as a route handler or
+// middleware anywhere a HandlerFunc can. A non-nil error returned by h is passed to
+// Context.Error, which renders it with the Config's ErrorHandler, and the handler chain is
+// aborted.
+func HandleErr(h ErrHandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		if err := h(c); err != nil {
+			c.Error(err)
+			c.Abort()
+		}
+	}
+}
+
+// HTTPError is an error rendered as a response with the given status code by the Config's
+// ErrorHandler.
+type HTTPError struct {
+	// Status is the HTTP status code of the response.
+	Status int
+	// Code is the application-specific error code of the response, which defaults to Status.
+	Code int
+	// Message is the message of the response, which defaults to the status text.
+	Message string
+	// Internal is the cause of the error, which is logged but never sent to the client.
+	Internal error
+}
+
+// NewHTTPError returns an HTTPError with the given status code and optional message.
+func NewHTTPError(status int, message ...string) *HTTPError {
+	e := &HTTPError{Status: status}
+	if len(message) > 0 {
+		e.Message = message[0]
+	}
+	return e
+}
+
+// WithInternal returns a copy of the error with the given cause.
+func (e *HTTPError) WithInternal(err error) *HTTPError {
+	c := *e
+	c.Internal = err
+	return &c
+}
+
+// Error returns the status code and message of the error, followed by its cause if any.
+func (e *HTTPError) Error() string {
+	if e.Internal == nil {
+		return fmt.Sprintf("%d %s", e.Status, e.message())
+	}
+	return fmt.Sprintf("%d %s: %v", e.Status, e.message(), e.Internal)
+}
+
+// Unwrap returns the cause of the error.
+func (e *HTTPError) Unwrap() error {
+	return e.Internal
+}
+
+// message returns the message of the error, or the text of its status code.
+func (e *HTTPError) message() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return fasthttp.StatusMessage(e.Status)
+}
+
+// Error renders err with the Config's ErrorHandler. Errors returned by the handlers of
+// HandleErr are passed to it, and so can the errors of methods such as JSONBody, File and
+// RedirectSafe, which are HTTPErrors with a suitable status code.
+func (c *Context) Error(err error) {
+	handler := defaultErrorHandler
+	if c.App != nil && c.App.Config.ErrorHandler != nil {
+		handler = c.App.Config.ErrorHandler
+	}
+	handler(c, err)
+}
+
+// defaultErrorHandler renders the error as a JSON response with the code and message of an
+// HTTPError, like JSONError, and other errors as 500 Internal Server Error. The fields of a
+// ValidationError are listed in its "errors" member. Server errors are logged with their cause.
+func defaultErrorHandler(c *Context, err error) {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		httpErr = &HTTPError{Status: StatusInternalServerError, Internal: err}
+	}
+	if httpErr.Status >= StatusInternalServerError && c.App != nil && c.App.Logger != nil {
+		c.App.Logger.Error("%s %s: %v", c.Method, c.Path, err)
+	}
+
+	code := httpErr.Code
+	if code == 0 {
+		code = httpErr.Status
+	}
+	body := map[string]any{
+		"code":    code,
+		"message": httpErr.message(),
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		body["errors"] = validationErr.Fields
+	}
+	c.JSON(httpErr.Status, body)
+}
@@ -0,0 +1,127 @@
+package lightning
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestHandleErr(t *testing.T) {
+	app := NewApp()
+	app.Use(HandleErr(func(c *Context) error {
+		if c.Header("X-Token") == "invalid" {
+			return &HTTPError{Status: StatusUnauthorized, Code: 40101, Message: "invalid token"}
+		}
+		c.Next()
+		return nil
+	}))
+	app.Get("/users/:id", HandleErr(func(c *Context) error {
+		id, err := c.ParamInt("id")
+		if err != nil {
+			return NewHTTPError(StatusBadRequest, "invalid id").WithInternal(err)
+		}
+		if id == 0 {
+			return errors.New("database unavailable")
+		}
+		c.Text(StatusOK, "user")
+		return nil
+	}))
+
+	tests := []struct {
+		path   string
+		token  string
+		status int
+		body   string
+	}{
+		{"/users/1", "", StatusOK, "user"},
+		{"/users/abc", "", StatusBadRequest, `{"code":400,"message":"invalid id"}`},
+		{"/users/0", "", StatusInternalServerError, `{"code":500,"message":"Internal Server Error"}`},
+		{"/users/1", "invalid", StatusUnauthorized, `{"code":40101,"message":"invalid token"}`},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodGet, tt.path)
+		ctx.Request.Header.Set("X-Token", tt.token)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("Expected status %d for '%s', got %d", tt.status, tt.path, ctx.Response.StatusCode())
+		}
+		if body := string(ctx.Response.Body()); body != tt.body {
+			t.Errorf("Expected body '%s' for '%s', got '%s'", tt.body, tt.path, body)
+		}
+	}
+}
+
+func TestConfigErrorHandler(t *testing.T) {
+	var handled error
+	app := NewApp(&Config{
+		ErrorHandler: func(c *Context, err error) {
+			handled = err
+			c.Text(StatusTeapot, err.Error())
+		},
+	})
+	app.Post("/items", HandleErr(func(c *Context) error {
+		var item struct {
+			Name string `json:"name" validate:"required"`
+		}
+		return c.JSONBody(&item, true)
+	}))
+
+	ctx := createFasthttpRequest(MethodPost, "/items")
+	ctx.Request.SetBody([]byte(`{}`))
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusTeapot {
+		t.Errorf("Expected status %d, got %d", StatusTeapot, ctx.Response.StatusCode())
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(handled, &validationErrors) {
+		t.Errorf("Expected the validation error to be wrapped, got %v", handled)
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := NewHTTPError(StatusServiceUnavailable).WithInternal(cause)
+	if err.Error() != "503 Service Unavailable: connection refused" {
+		t.Errorf("Unexpected error message '%s'", err.Error())
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected the error to wrap its cause")
+	}
+	if msg := NewHTTPError(StatusNotFound, "no such user").Error(); msg != "404 no such user" {
+		t.Errorf("Unexpected error message '%s'", msg)
+	}
+}
+
+func TestContextMethodErrors(t *testing.T) {
+	c, _ := createTestContext(MethodPost, "/test", []byte(`invalid json`))
+
+	var v map[string]any
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"JSONBody", c.JSONBody(&v), StatusBadRequest},
+		{"File", c.File("missing.txt"), StatusNotFound},
+		{"FileFromSafeDir", c.FileFromSafeDir(t.TempDir(), "../secret"), StatusForbidden},
+		{"RedirectSafe", c.RedirectSafe(StatusFound, "https://evil.com"), StatusBadRequest},
+	}
+	for _, tt := range tests {
+		var httpErr *HTTPError
+		if !errors.As(tt.err, &httpErr) || httpErr.Status != tt.status {
+			t.Errorf("Expected %s to return an HTTPError with status %d, got %v", tt.name, tt.status, tt.err)
+		}
+	}
+
+	if err := c.File("missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected the cause to be kept, got %v", err)
+	}
+
+	c.Error(c.RedirectSafe(StatusFound, "//evil.com"))
+	if c.Status() != StatusBadRequest || !strings.Contains(string(c.Body()), "Bad Request") {
+		t.Errorf("Expected a 400 response, got %d '%s'", c.Status(), c.Body())
+	}
+}
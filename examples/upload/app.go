@@ -0,0 +1,37 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/go-labx/lightning"
+)
+
+func main() {
+	app := lightning.DefaultApp()
+
+	app.Post("/avatars", lightning.HandleErr(func(ctx *lightning.Context) error {
+		// Get the uploaded file, whose size and type were checked against the route's limits
+		file, err := ctx.FormFile("avatar")
+		if err != nil {
+			return err
+		}
+
+		// Never use the file name sent by the client as is
+		dst := filepath.Join("uploads", filepath.Base(file.Filename))
+		if err := ctx.SaveUploadedFile(file, dst); err != nil {
+			return err
+		}
+
+		ctx.Success(lightning.Map{
+			"user": ctx.FormValue("user"),
+			"file": dst,
+		})
+		return nil
+	})).With(lightning.WithUploadLimits(lightning.UploadLimits{
+		MaxFiles:     1,
+		MaxFileSize:  2 << 20,
+		AllowedTypes: []string{"image/png", "image/jpeg"},
+	}))
+
+	app.Run()
+}
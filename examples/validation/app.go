@@ -29,5 +29,11 @@ func main() {
 		ctx.Success(user)
 	})
 
+	// Handle binds and validates the request body and renders the response, and
+	// validation errors are returned as 400 Bad Request responses
+	app.Post("/users", lightning.Handle(func(ctx *lightning.Context, user *User) (*User, error) {
+		return user, nil
+	})).With(lightning.WithTypes[*User, *User]())
+
 	app.Run()
 }
@@ -4,9 +4,14 @@ package lightning
 type Group struct {
 	app               *Application
 	parent            *Group
+	host              string
+	version           string
 	prefix            string
 	middlewares       []HandlerFunc
 	cachedMiddlewares []HandlerFunc
+	deprecation       *deprecation
+	noAutoHead        bool
+	noAutoOptions     bool
 }
 
 // newGroup creates a new Group with the given prefix and Application.
@@ -27,6 +32,15 @@ func (g *Group) getFullPrefix() string {
 	return g.parent.getFullPrefix() + g.prefix
 }
 
+// getHost returns the host pattern of the Group or its ancestors, or an empty string if the
+// Group belongs to the default router.
+func (g *Group) getHost() string {
+	if g.host != "" || g.parent == nil {
+		return g.host
+	}
+	return g.parent.getHost()
+}
+
 // getMiddlewares returns the middleware functions of the Group and its ancestors.
 // Uses cached result when available to avoid repeated allocations.
 func (g *Group) getMiddlewares() []HandlerFunc {
@@ -47,6 +61,28 @@ func (g *Group) getMiddlewares() []HandlerFunc {
 	return g.cachedMiddlewares
 }
 
+// autoHeadDisabled reports whether automatic HEAD handling is disabled for the Group or its ancestors.
+func (g *Group) autoHeadDisabled() bool {
+	return g.noAutoHead || (g.parent != nil && g.parent.autoHeadDisabled())
+}
+
+// autoOptionsDisabled reports whether automatic OPTIONS handling is disabled for the Group or its ancestors.
+func (g *Group) autoOptionsDisabled() bool {
+	return g.noAutoOptions || (g.parent != nil && g.parent.autoOptionsDisabled())
+}
+
+// DisableAutoHead stops HEAD requests from being answered by the GET routes of the Group and its children.
+func (g *Group) DisableAutoHead() {
+	g.app.mustNotServe("Group.DisableAutoHead")
+	g.noAutoHead = true
+}
+
+// DisableAutoOptions stops OPTIONS requests from being answered automatically for the routes of the Group and its children.
+func (g *Group) DisableAutoOptions() {
+	g.app.mustNotServe("Group.DisableAutoOptions")
+	g.noAutoOptions = true
+}
+
 // Group creates a new Group with the given prefix and adds it as a child of the current Group.
 func (g *Group) Group(prefix string) *Group {
 	group := newGroup(g.app, prefix)
@@ -56,50 +92,84 @@ func (g *Group) Group(prefix string) *Group {
 
 // AddRoute adds a new route to the Application with the given method, pattern, and handlers.
 // The route's path is the full prefix of the Group concatenated with the given pattern.
-// The route's handlers are the middleware functions of the Group and its ancestors concatenated with the given handlers.
-func (g *Group) AddRoute(method string, pattern string, handlers []HandlerFunc) {
-	handlers = append(g.getMiddlewares(), handlers...)
+// The middleware functions of the Group and its ancestors run before the given handlers.
+// It panics if the route conflicts with an existing route, see Application.AddRoute.
+func (g *Group) AddRoute(method string, pattern string, handlers []HandlerFunc, options ...RouteOption) *Route {
+	r, err := g.TryAddRoute(method, pattern, handlers, options...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// TryAddRoute adds a new route like AddRoute, but returns an error instead of panicking if
+// the route or its name conflicts with an existing route, see Application.TryAddRoute.
+func (g *Group) TryAddRoute(method string, pattern string, handlers []HandlerFunc, options ...RouteOption) (*Route, error) {
 	path := g.getFullPrefix() + pattern
-	g.app.AddRoute(method, path, handlers)
+	return g.app.addRoute(method, path, handlers, g, options)
+}
+
+// RemoveRoute removes the route registered for the method and pattern, which is prefixed with
+// the full prefix of the Group, for the host and API version of the Group, see
+// Application.RemoveRoute.
+func (g *Group) RemoveRoute(method string, pattern string) bool {
+	return g.app.removeRoute(g.getHost(), g.getVersion(), method, g.getFullPrefix()+pattern)
 }
 
 // Use adds the given middleware functions to the Group's middleware stack.
+// They run for all routes of the Group and its children, including the routes added before.
+// Use panics once the Application serves requests, see Application.RequestHandler.
 func (g *Group) Use(middlewares ...HandlerFunc) {
+	g.app.mustNotServe("Group.Use")
 	g.middlewares = append(g.middlewares, middlewares...)
 	g.cachedMiddlewares = nil
 }
 
 // Get adds a new GET route to the Application with the given pattern and handlers.
-func (g *Group) Get(pattern string, handlers ...HandlerFunc) {
-	g.AddRoute(MethodGet, pattern, handlers)
+func (g *Group) Get(pattern string, handlers ...HandlerFunc) *Route {
+	return g.AddRoute(MethodGet, pattern, handlers)
 }
 
 // Post adds a new POST route to the Application with the given pattern and handlers.
-func (g *Group) Post(pattern string, handlers ...HandlerFunc) {
-	g.AddRoute(MethodPost, pattern, handlers)
+func (g *Group) Post(pattern string, handlers ...HandlerFunc) *Route {
+	return g.AddRoute(MethodPost, pattern, handlers)
 }
 
 // Put adds a new PUT route to the Application with the given pattern and handlers.
-func (g *Group) Put(pattern string, handlers ...HandlerFunc) {
-	g.AddRoute(MethodPut, pattern, handlers)
+func (g *Group) Put(pattern string, handlers ...HandlerFunc) *Route {
+	return g.AddRoute(MethodPut, pattern, handlers)
 }
 
 // Delete adds a new DELETE route to the Application with the given pattern and handlers.
-func (g *Group) Delete(pattern string, handlers ...HandlerFunc) {
-	g.AddRoute(MethodDelete, pattern, handlers)
+func (g *Group) Delete(pattern string, handlers ...HandlerFunc) *Route {
+	return g.AddRoute(MethodDelete, pattern, handlers)
 }
 
 // Head adds a new HEAD route to the Application with the given pattern and handlers.
-func (g *Group) Head(pattern string, handlers ...HandlerFunc) {
-	g.AddRoute(MethodHead, pattern, handlers)
+func (g *Group) Head(pattern string, handlers ...HandlerFunc) *Route {
+	return g.AddRoute(MethodHead, pattern, handlers)
 }
 
 // Options adds a new OPTIONS route to the Application with the given pattern and handlers.
-func (g *Group) Options(pattern string, handlers ...HandlerFunc) {
-	g.AddRoute(MethodOptions, pattern, handlers)
+func (g *Group) Options(pattern string, handlers ...HandlerFunc) *Route {
+	return g.AddRoute(MethodOptions, pattern, handlers)
 }
 
 // Patch adds a new PATCH route to the Application with the given pattern and handlers.
-func (g *Group) Patch(pattern string, handlers ...HandlerFunc) {
-	g.AddRoute(MethodPatch, pattern, handlers)
+func (g *Group) Patch(pattern string, handlers ...HandlerFunc) *Route {
+	return g.AddRoute(MethodPatch, pattern, handlers)
+}
+
+// Any adds a new route matching every method to the Application with the given pattern and handlers.
+func (g *Group) Any(pattern string, handlers ...HandlerFunc) *Route {
+	return g.AddRoute(MethodAny, pattern, handlers)
+}
+
+// Match adds a new route for each of the given methods to the Application with the given pattern and handlers.
+func (g *Group) Match(methods []string, pattern string, handlers ...HandlerFunc) []*Route {
+	routes := make([]*Route, 0, len(methods))
+	for _, method := range methods {
+		routes = append(routes, g.AddRoute(method, pattern, handlers))
+	}
+	return routes
 }
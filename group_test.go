@@ -86,7 +86,7 @@ func TestGroup_AddRoute(t *testing.T) {
 	handlers := []HandlerFunc{func(c *Context) {}}
 	group.AddRoute(MethodGet, "/path", handlers)
 
-	searchHandlers, _ := app.router.findRoute(MethodGet, "/prefix/path")
+	searchHandlers, _ := app.routes().router.findRoute(MethodGet, "/prefix/path")
 	if reflect.ValueOf(searchHandlers[0]) != reflect.ValueOf(handlers[0]) {
 		t.Errorf("Expected handlers to be '%v', but got '%v'", searchHandlers[0], handlers[0])
 	}
@@ -123,7 +123,7 @@ func TestGroup_Get(t *testing.T) {
 	handlers := []HandlerFunc{func(c *Context) {}}
 	group.Get("/path", handlers...)
 
-	searchHandlers, _ := app.router.findRoute(MethodGet, "/prefix/path")
+	searchHandlers, _ := app.routes().router.findRoute(MethodGet, "/prefix/path")
 	if reflect.ValueOf(searchHandlers[0]) != reflect.ValueOf(handlers[0]) {
 		t.Errorf("Expected handlers to be '%v', but got '%v'", searchHandlers[0], handlers[0])
 	}
@@ -135,7 +135,7 @@ func TestGroup_Post(t *testing.T) {
 	handlers := []HandlerFunc{func(c *Context) {}}
 	group.Post("/path", handlers...)
 
-	searchHandlers, _ := app.router.findRoute(MethodPost, "/prefix/path")
+	searchHandlers, _ := app.routes().router.findRoute(MethodPost, "/prefix/path")
 	if reflect.ValueOf(searchHandlers[0]) != reflect.ValueOf(handlers[0]) {
 		t.Errorf("Expected handlers to be '%v', but got '%v'", searchHandlers[0], handlers[0])
 	}
@@ -147,7 +147,7 @@ func TestGroup_Put(t *testing.T) {
 	handlers := []HandlerFunc{func(c *Context) {}}
 	group.Put("/path", handlers...)
 
-	searchHandlers, _ := app.router.findRoute(MethodPut, "/prefix/path")
+	searchHandlers, _ := app.routes().router.findRoute(MethodPut, "/prefix/path")
 	if reflect.ValueOf(searchHandlers[0]) != reflect.ValueOf(handlers[0]) {
 		t.Errorf("Expected handlers to be '%v', but got '%v'", searchHandlers[0], handlers[0])
 	}
@@ -159,7 +159,7 @@ func TestGroup_Delete(t *testing.T) {
 	handlers := []HandlerFunc{func(c *Context) {}}
 	group.Delete("/path", handlers...)
 
-	searchHandlers, _ := app.router.findRoute(MethodDelete, "/prefix/path")
+	searchHandlers, _ := app.routes().router.findRoute(MethodDelete, "/prefix/path")
 	if reflect.ValueOf(searchHandlers[0]) != reflect.ValueOf(handlers[0]) {
 		t.Errorf("Expected handlers to be '%v', but got '%v'", searchHandlers[0], handlers[0])
 	}
@@ -171,7 +171,7 @@ func TestGroup_Head(t *testing.T) {
 	handlers := []HandlerFunc{func(c *Context) {}}
 	group.Head("/path", handlers...)
 
-	searchHandlers, _ := app.router.findRoute(MethodHead, "/prefix/path")
+	searchHandlers, _ := app.routes().router.findRoute(MethodHead, "/prefix/path")
 	if reflect.ValueOf(searchHandlers[0]) != reflect.ValueOf(handlers[0]) {
 		t.Errorf("Expected handlers to be '%v', but got '%v'", searchHandlers[0], handlers[0])
 	}
@@ -183,7 +183,7 @@ func TestGroup_Patch(t *testing.T) {
 	handlers := []HandlerFunc{func(c *Context) {}}
 	group.Patch("/path", handlers...)
 
-	searchHandlers, _ := app.router.findRoute(MethodPatch, "/prefix/path")
+	searchHandlers, _ := app.routes().router.findRoute(MethodPatch, "/prefix/path")
 	if reflect.ValueOf(searchHandlers[0]) != reflect.ValueOf(handlers[0]) {
 		t.Errorf("Expected handlers to be '%v', but got '%v'", searchHandlers[0], handlers[0])
 	}
@@ -195,8 +195,104 @@ func TestGroup_Options(t *testing.T) {
 	handlers := []HandlerFunc{func(c *Context) {}}
 	group.Options("/path", handlers...)
 
-	searchHandlers, _ := app.router.findRoute(MethodOptions, "/prefix/path")
+	searchHandlers, _ := app.routes().router.findRoute(MethodOptions, "/prefix/path")
 	if reflect.ValueOf(searchHandlers[0]) != reflect.ValueOf(handlers[0]) {
 		t.Errorf("Expected handlers to be '%v', but got '%v'", searchHandlers[0], handlers[0])
 	}
 }
+
+func TestGroup_DisableAutoHead(t *testing.T) {
+	app := NewApp()
+	group := app.Group("/api")
+	group.DisableAutoHead()
+	child := group.Group("/v1")
+	child.Get("/path", func(c *Context) {
+		c.Text(StatusOK, "ok")
+	})
+	app.Get("/path", func(c *Context) {
+		c.Text(StatusOK, "ok")
+	})
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(MethodHead)
+	ctx.Request.Header.SetRequestURI("/api/v1/path")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, but got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+
+	ctx = &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(MethodHead)
+	ctx.Request.Header.SetRequestURI("/path")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusOK {
+		t.Errorf("Expected status %d, but got %d", StatusOK, ctx.Response.StatusCode())
+	}
+}
+
+func TestGroup_DisableAutoOptions(t *testing.T) {
+	app := NewApp()
+	group := app.Group("/api")
+	group.DisableAutoOptions()
+	group.Get("/path", func(c *Context) {})
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(MethodOptions)
+	ctx.Request.Header.SetRequestURI("/api/path")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, but got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+	if allow := string(ctx.Response.Header.Peek(HeaderAllow)); allow != "GET, HEAD" {
+		t.Errorf("Expected Allow header 'GET, HEAD', but got '%s'", allow)
+	}
+}
+
+func TestGroup_TryAddRoute(t *testing.T) {
+	app := NewApp()
+	app.Get("/api/users/:id", func(c *Context) {})
+	group := app.Group("/api")
+
+	_, err := group.TryAddRoute(MethodGet, "/users/:userId", []HandlerFunc{func(c *Context) {}})
+	if _, ok := err.(*RouteConflictError); !ok {
+		t.Errorf("Expected *RouteConflictError, but got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for conflicting group route")
+		}
+	}()
+	group.Get("/users/:userId", func(c *Context) {})
+}
+
+func TestGroup_AnyAndMatch(t *testing.T) {
+	app := NewApp()
+	group := app.Group("/api")
+	group.Any("/proxy/*path", func(c *Context) {
+		c.Text(StatusOK, "any")
+	})
+	group.Match([]string{MethodPut, "MKCOL"}, "/items", func(c *Context) {
+		c.Text(StatusOK, c.Method)
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{MethodDelete, "/api/proxy/a", "any"},
+		{MethodPut, "/api/items", MethodPut},
+		{"MKCOL", "/api/items", "MKCOL"},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(tt.method, tt.path)
+		app.serveRequest(ctx)
+		if body := string(ctx.Response.Body()); body != tt.body {
+			t.Errorf("Expected '%s' for %s %s, got '%s'", tt.body, tt.method, tt.path, body)
+		}
+	}
+}
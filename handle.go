@@ -0,0 +1,137 @@
+package lightning
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// StatusCoder is implemented by responses of typed handlers that set their status code,
+// see Handle.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Handle returns a HandlerFunc that binds the request to a Req, calls fn with it and
+// renders the Resp it returns.
+//
+// The request body is decoded into Req according to its Content-Type, like Context.Bind.
+// If Req is a struct or a pointer to one, the fields tagged with `path`, `query`, `header`
+// and `cookie` are then set to the URL parameter, query parameter, header and cookie they
+// name, like Context.BindQuery, and the struct is validated with its `validate` tags.
+// Binding errors are 400 Bad Request *HTTPErrors.
+//
+// The response is rendered as XML if the Accept header prefers it, and as JSON otherwise,
+// with status 200 OK unless Resp implements StatusCoder. A nil response is rendered as
+// 204 No Content. Errors, including binding errors, are passed to Context.Error, which
+// maps them to a status code with the Config's ErrorHandler, and abort the handler chain.
+//
+// The route can report Req and Resp with the WithTypes option.
+func Handle[Req, Resp any](fn func(c *Context, req Req) (Resp, error)) HandlerFunc {
+	return func(c *Context) {
+		var req Req
+		if err := c.bindRequest(reflect.ValueOf(&req).Elem()); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		resp, err := fn(c, req)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		c.render(resp)
+	}
+}
+
+// WithTypes returns a RouteOption that records the request and response types of the
+// route, typically those of its handler created with Handle. They are reported by
+// Route.RequestType and Route.ResponseType, and in the RouteInfo of the route.
+func WithTypes[Req, Resp any]() RouteOption {
+	return func(r *Route) {
+		r.request, r.response = reflect.TypeFor[Req](), reflect.TypeFor[Resp]()
+	}
+}
+
+// bindRequest binds the request to v with Bind, allocating the struct v points to if it is
+// a pointer to one, see Handle.
+func (c *Context) bindRequest(v reflect.Value) error {
+	if v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.Struct {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	return c.Bind(v.Addr().Interface())
+}
+
+// render writes the response of a typed handler, see Handle.
+func (c *Context) render(resp any) {
+	v := reflect.ValueOf(resp)
+	if !v.IsValid() || (v.Kind() == reflect.Pointer || v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		c.SetStatus(StatusNoContent)
+		return
+	}
+
+	code := StatusOK
+	if coder, ok := resp.(StatusCoder); ok {
+		code = coder.StatusCode()
+	}
+	if negotiate(c.Header(HeaderAccept), MIMEApplicationJSON, MIMEApplicationXML) == MIMEApplicationXML {
+		c.XML(code, resp)
+		return
+	}
+	c.JSON(code, resp)
+}
+
+// negotiate returns the offered media type that the Accept header prefers, or the first
+// offer if the header is empty or accepts none of them. Structured syntax suffixes are
+// matched, so `application/vnd.acme.v2+json` accepts `application/json`.
+func negotiate(accept string, offers ...string) string {
+	best, bestQuality := offers[0], 0.0
+	for accept != "" {
+		var mediaRange string
+		mediaRange, accept, _ = strings.Cut(accept, ",")
+		mediaType, params, _ := strings.Cut(mediaRange, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		quality := 1.0
+		for params != "" {
+			var param string
+			param, params, _ = strings.Cut(params, ";")
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality <= bestQuality {
+			continue
+		}
+
+		for _, offer := range offers {
+			if acceptsMediaType(mediaType, offer) {
+				best, bestQuality = offer, quality
+				break
+			}
+		}
+	}
+	return best
+}
+
+// acceptsMediaType reports whether the media range of an Accept header accepts the offer.
+func acceptsMediaType(mediaRange string, offer string) bool {
+	if mediaRange == "*/*" || mediaRange == offer {
+		return true
+	}
+	rangeType, rangeSubtype, _ := strings.Cut(mediaRange, "/")
+	offerType, offerSubtype, _ := strings.Cut(offer, "/")
+	if rangeType != offerType {
+		return false
+	}
+	if rangeSubtype == "*" {
+		return true
+	}
+	_, suffix, ok := strings.Cut(rangeSubtype, "+")
+	return ok && suffix == offerSubtype
+}
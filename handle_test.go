@@ -0,0 +1,170 @@
+package lightning
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type createItemRequest struct {
+	Shop  string   `path:"shop"`
+	Name  string   `json:"name" validate:"required"`
+	Price float64  `json:"price" validate:"gte=0"`
+	Tags  []string `query:"tag"`
+	Token string   `header:"X-Token"`
+}
+
+type itemResponse struct {
+	Shop  string   `json:"shop" xml:"shop"`
+	Name  string   `json:"name" xml:"name"`
+	Price float64  `json:"price" xml:"price"`
+	Tags  []string `json:"tags" xml:"tag"`
+	Token string   `json:"token" xml:"token"`
+}
+
+type createdResponse struct {
+	ID int `json:"id"`
+}
+
+func (createdResponse) StatusCode() int {
+	return StatusCreated
+}
+
+func TestHandle(t *testing.T) {
+	app := NewApp()
+	app.Post("/shops/:shop/items", Handle(func(c *Context, req createItemRequest) (*itemResponse, error) {
+		if req.Name == "forbidden" {
+			return nil, NewHTTPError(StatusForbidden)
+		}
+		if req.Name == "empty" {
+			return nil, nil
+		}
+		return &itemResponse{Shop: req.Shop, Name: req.Name, Price: req.Price, Tags: req.Tags, Token: req.Token}, nil
+	}))
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		accept string
+		status int
+		want   string
+	}{
+		{"bound", "/shops/acme/items?tag=a&tag=b", `{"name":"pen","price":1.5}`, "",
+			StatusOK, `{"shop":"acme","name":"pen","price":1.5,"tags":["a","b"],"token":"secret"}`},
+		{"xml", "/shops/acme/items", `{"name":"pen","price":1.5}`, "text/html, application/xml;q=0.9, application/json;q=0.8",
+			StatusOK, `<itemResponse><shop>acme</shop><name>pen</name><price>1.5</price><token>secret</token></itemResponse>`},
+		{"invalid", "/shops/acme/items", `{"price":1}`, "", StatusBadRequest,
+			`{"code":400,"errors":[{"field":"name","path":"Name","rule":"required","message":"name is a required field"}],"message":"Bad Request"}`},
+		{"malformed", "/shops/acme/items", `{`, "", StatusBadRequest, `{"code":400,"message":"Bad Request"}`},
+		{"error", "/shops/acme/items", `{"name":"forbidden"}`, "", StatusForbidden, `{"code":403,"message":"Forbidden"}`},
+		{"nil", "/shops/acme/items", `{"name":"empty"}`, "", StatusNoContent, ""},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodPost, tt.path)
+		ctx.Request.Header.Set("X-Token", "secret")
+		ctx.Request.Header.Set(HeaderAccept, tt.accept)
+		ctx.Request.SetBody([]byte(tt.body))
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("%s: Expected status %d, got %d", tt.name, tt.status, ctx.Response.StatusCode())
+		}
+		if body := strings.TrimSpace(string(ctx.Response.Body())); body != tt.want {
+			t.Errorf("%s: Expected body '%s', got '%s'", tt.name, tt.want, body)
+		}
+	}
+}
+
+func TestHandleStatusCoderAndQueryErrors(t *testing.T) {
+	type listRequest struct {
+		Page int `query:"page"`
+	}
+	app := NewApp()
+	app.Get("/items", Handle(func(c *Context, req *listRequest) (createdResponse, error) {
+		return createdResponse{ID: req.Page}, nil
+	}))
+
+	ctx := createFasthttpRequest(MethodGet, "/items?page=3")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusCreated {
+		t.Errorf("Expected status %d, got %d", StatusCreated, ctx.Response.StatusCode())
+	}
+	if body := string(ctx.Response.Body()); body != `{"id":3}` {
+		t.Errorf("Expected body '{\"id\":3}', got '%s'", body)
+	}
+
+	ctx = createFasthttpRequest(MethodGet, "/items?page=three")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", StatusBadRequest, ctx.Response.StatusCode())
+	}
+	if body := string(ctx.Response.Body()); body != `{"code":400,"message":"invalid query parameter 'page'"}` {
+		t.Errorf("Expected an invalid parameter message, got '%s'", body)
+	}
+}
+
+func TestHandleAbortsChain(t *testing.T) {
+	app := NewApp()
+	after := false
+	app.Use(func(c *Context) {
+		c.Next()
+		after = !c.IsAborted()
+	})
+	app.Post("/items", Handle(func(c *Context, req createItemRequest) (itemResponse, error) {
+		return itemResponse{}, nil
+	}))
+
+	ctx := createFasthttpRequest(MethodPost, "/items")
+	ctx.Request.SetBody([]byte(`{}`))
+	app.serveRequest(ctx)
+	if after {
+		t.Error("Expected the binding error to abort the handler chain")
+	}
+}
+
+func TestHandleRouteTypes(t *testing.T) {
+	app := NewApp()
+	typed := app.AddRoute(MethodPost, "/items", []HandlerFunc{Handle(func(c *Context, req createItemRequest) (*itemResponse, error) {
+		return nil, nil
+	})}, WithTypes[createItemRequest, *itemResponse]())
+	plain := app.Get("/items", func(c *Context) {})
+
+	if typed.RequestType() != reflect.TypeFor[createItemRequest]() {
+		t.Errorf("Expected request type createItemRequest, got %v", typed.RequestType())
+	}
+	if typed.ResponseType() != reflect.TypeFor[*itemResponse]() {
+		t.Errorf("Expected response type *itemResponse, got %v", typed.ResponseType())
+	}
+	if plain.RequestType() != nil || plain.ResponseType() != nil {
+		t.Errorf("Expected no types for a plain handler, got %v and %v", plain.RequestType(), plain.ResponseType())
+	}
+
+	for _, info := range app.Routes() {
+		if info.Method != MethodPost {
+			continue
+		}
+		if info.Request != "lightning.createItemRequest" || info.Response != "*lightning.itemResponse" {
+			t.Errorf("Expected the route info to name the types, got '%s' and '%s'", info.Request, info.Response)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", MIMEApplicationJSON},
+		{"*/*", MIMEApplicationJSON},
+		{"application/xml", MIMEApplicationXML},
+		{"text/xml, application/*;q=0.5", MIMEApplicationJSON},
+		{"application/json;q=0.5, application/xml", MIMEApplicationXML},
+		{"application/vnd.acme.v2+xml", MIMEApplicationXML},
+		{"text/html", MIMEApplicationJSON},
+	}
+	for _, tt := range tests {
+		if got := negotiate(tt.accept, MIMEApplicationJSON, MIMEApplicationXML); got != tt.want {
+			t.Errorf("Expected '%s' for Accept '%s', got '%s'", tt.want, tt.accept, got)
+		}
+	}
+}
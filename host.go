@@ -0,0 +1,80 @@
+package lightning
+
+import (
+	"fmt"
+	"strings"
+)
+
+// hostRouter holds the routes registered for a host pattern such as `api.example.com`
+// or `:tenant.example.com`.
+type hostRouter struct {
+	pattern string
+	labels  []string
+	router  *router
+}
+
+// newHostRouter returns a host router for the given pattern, with the routing configuration
+// of the default router.
+func newHostRouter(pattern string, defaults *router) *hostRouter {
+	labels := strings.Split(pattern, ".")
+	for _, label := range labels {
+		if label == "" || label == ":" {
+			panic(fmt.Sprintf("invalid host pattern '%s'", pattern))
+		}
+	}
+
+	r := newRouter()
+	r.constraints = defaults.constraints
+	r.strictSlash = defaults.strictSlash
+	return &hostRouter{pattern: pattern, labels: labels, router: r}
+}
+
+// isStatic reports whether the host pattern has no parameters.
+func (h *hostRouter) isStatic() bool {
+	for _, label := range h.labels {
+		if label[0] == ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// match reports whether host matches the pattern label by label, ignoring case, and appends
+// the values of the pattern's parameters to params. Each parameter matches a single label.
+func (h *hostRouter) match(host string, params *Params) bool {
+	start := len(*params)
+	for i, label := range h.labels {
+		value, rest, found := strings.Cut(host, ".")
+		if found != (i < len(h.labels)-1) || value == "" {
+			*params = (*params)[:start]
+			return false
+		}
+		if label[0] == ':' {
+			*params = append(*params, Param{Key: label[1:], Value: value})
+		} else if !strings.EqualFold(value, label) {
+			*params = (*params)[:start]
+			return false
+		}
+		host = rest
+	}
+	return true
+}
+
+// Host returns a Group whose routes are only matched for requests to the given host.
+// The pattern is a hostname without port, compared case-insensitively; labels starting
+// with a colon are parameters matching a single label, e.g. `:tenant.example.com`, whose
+// values are available with Context.Param.
+//
+// Static host patterns take precedence over patterns with parameters, which are tried in
+// order of registration. Requests to hosts matching no pattern are routed by the routes
+// registered on the Application itself; requests to a matching host are only routed by
+// the routes of that host.
+func (app *Application) Host(pattern string) *Group {
+	app.updateRoutes(func(t *routingTable) error {
+		t.addHost(pattern)
+		return nil
+	})
+	group := newGroup(app, "")
+	group.host = pattern
+	return group
+}
@@ -0,0 +1,141 @@
+package lightning
+
+import (
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func createHostRequest(method, host, path string) *fasthttp.RequestCtx {
+	ctx := createFasthttpRequest(method, path)
+	ctx.Request.Header.SetHost(host)
+	return ctx
+}
+
+func TestHostRouting(t *testing.T) {
+	app := NewApp()
+	app.Get("/users", func(c *Context) {
+		c.Text(StatusOK, "default")
+	})
+	app.Host("api.example.com").Get("/users", func(c *Context) {
+		c.Text(StatusOK, "api")
+	})
+	app.Host(":tenant.example.com").Get("/users", func(c *Context) {
+		c.Text(StatusOK, "tenant "+c.Param("tenant"))
+	})
+
+	tests := []struct {
+		host   string
+		status int
+		body   string
+	}{
+		{"api.example.com", StatusOK, "api"},
+		{"API.Example.com:8080", StatusOK, "api"},
+		{"acme.example.com", StatusOK, "tenant acme"},
+		{"a.b.example.com", StatusOK, "default"},
+		{"example.com", StatusOK, "default"},
+		{"localhost:8080", StatusOK, "default"},
+	}
+	for _, tt := range tests {
+		ctx := createHostRequest(MethodGet, tt.host, "/users")
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("Expected status %d for host '%s', got %d", tt.status, tt.host, ctx.Response.StatusCode())
+		}
+		if body := string(ctx.Response.Body()); body != tt.body {
+			t.Errorf("Expected body '%s' for host '%s', got '%s'", tt.body, tt.host, body)
+		}
+	}
+}
+
+func TestHostRoutingNoFallbackForMatchedHost(t *testing.T) {
+	app := NewApp()
+	app.Get("/health", func(c *Context) {
+		c.Text(StatusOK, "ok")
+	})
+	app.Host("api.example.com").Get("/users", func(c *Context) {
+		c.Text(StatusOK, "api")
+	})
+
+	ctx := createHostRequest(MethodGet, "api.example.com", "/health")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusNotFound {
+		t.Errorf("Expected status %d, got %d", StatusNotFound, ctx.Response.StatusCode())
+	}
+}
+
+func TestHostParamsWithPathParams(t *testing.T) {
+	app := NewApp()
+	app.Host(":tenant.:region.example.com").Group("/api").Get("/users/:id", func(c *Context) {
+		c.Text(StatusOK, c.Param("tenant")+"|"+c.Param("region")+"|"+c.Param("id"))
+	})
+
+	ctx := createHostRequest(MethodGet, "acme.eu.example.com", "/api/users/42")
+	app.serveRequest(ctx)
+	if body := string(ctx.Response.Body()); body != "acme|eu|42" {
+		t.Errorf("Expected body 'acme|eu|42', got '%s'", body)
+	}
+}
+
+func TestHostPrecedence(t *testing.T) {
+	app := NewApp()
+	app.Host(":tenant.example.com").Get("/", func(c *Context) {
+		c.Text(StatusOK, "tenant")
+	})
+	app.Host("www.example.com").Get("/", func(c *Context) {
+		c.Text(StatusOK, "www")
+	})
+
+	ctx := createHostRequest(MethodGet, "www.example.com", "/")
+	app.serveRequest(ctx)
+	if body := string(ctx.Response.Body()); body != "www" {
+		t.Errorf("Expected static host to take precedence, got '%s'", body)
+	}
+}
+
+func TestHostSharesRouter(t *testing.T) {
+	app := NewApp()
+	app.Host("api.example.com").Get("/a", func(c *Context) {})
+
+	defer func() {
+		if _, ok := recover().(*RouteConflictError); !ok {
+			t.Error("Expected a RouteConflictError for the same host and pattern")
+		}
+	}()
+	app.Host("api.example.com").Get("/a", func(c *Context) {})
+}
+
+func TestHostInvalidPattern(t *testing.T) {
+	for _, pattern := range []string{"", "api..example.com", ":.example.com"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for host pattern '%s'", pattern)
+				}
+			}()
+			NewApp().Host(pattern)
+		}()
+	}
+}
+
+func TestHostRouterMatch(t *testing.T) {
+	h := newHostRouter(":tenant.example.com", newRouter())
+
+	var params Params
+	if !h.match("acme.example.com", &params) {
+		t.Fatal("Expected host to match")
+	}
+	if params.Get("tenant") != "acme" {
+		t.Errorf("Expected tenant 'acme', got '%s'", params.Get("tenant"))
+	}
+
+	params = params[:0]
+	for _, host := range []string{"example.com", "acme.example.org", ".example.com", "acme.example.com.evil"} {
+		if h.match(host, &params) {
+			t.Errorf("Expected host '%s' not to match", host)
+		}
+		if len(params) != 0 {
+			t.Errorf("Expected no params for '%s', got %v", host, params)
+		}
+	}
+}
@@ -1,14 +1,20 @@
 package lightning
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"os/signal"
 	"path"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"text/template"
 
@@ -28,29 +34,62 @@ type Map map[string]any
 // Application is the main struct that holds the router, middlewares, and configuration.
 type Application struct {
 	Config        *Config
-	router        *router
+	table         atomic.Pointer[routingTable]
+	tableMu       sync.Mutex
+	serving       atomic.Bool
+	buildOnce     sync.Once
 	middlewares   []HandlerFunc
+	notFound      []HandlerFunc
+	notAllowed    []HandlerFunc
+	autoOptions   []HandlerFunc
 	htmlTemplates *template.Template
 	funcMap       template.FuncMap
+	mounted       []*Application
 
 	Logger *lightlog.ConsoleLogger
 
 	server         *fasthttp.Server
+	baseCtx        context.Context
+	cancelBase     context.CancelCauseFunc
 	mu             sync.Mutex
 	contextPool    sync.Pool
 	trustedProxies []*net.IPNet
+	validation     atomic.Pointer[validation]
 }
 
+// TrailingSlashPolicy controls how a trailing slash in the request path is matched.
+type TrailingSlashPolicy int
+
+const (
+	// TrailingSlashIgnore matches paths regardless of a trailing slash, so `/users/` and
+	// `/users` reach the same route. This is the default.
+	TrailingSlashIgnore TrailingSlashPolicy = iota
+	// TrailingSlashRedirect only matches the path as registered, and redirects requests
+	// for the path with or without the trailing slash to it.
+	TrailingSlashRedirect
+	// TrailingSlashStrict only matches the path as registered.
+	TrailingSlashStrict
+)
+
 // Config holds the configuration for the Application.
 type Config struct {
-	AppName            string
-	JSONEncoder        JSONMarshal
-	JSONDecoder        JSONUnmarshal
-	NotFoundHandler    HandlerFunc
-	EnableDebug        bool
-	DebugToken         string
-	MaxRequestBodySize int64
-	TrustedProxies     []string
+	AppName                 string
+	JSONEncoder             JSONMarshal
+	JSONDecoder             JSONUnmarshal
+	NotFoundHandler         HandlerFunc
+	MethodNotAllowedHandler HandlerFunc
+	ErrorHandler            func(*Context, error)
+	Validator               Validator
+	DisableAutoHead         bool
+	DisableAutoOptions      bool
+	TrailingSlash           TrailingSlashPolicy
+	RedirectCleanPath       bool
+	RedirectCaseInsensitive bool
+	DefaultVersion          string
+	EnableDebug             bool
+	DebugToken              string
+	MaxRequestBodySize      int64
+	TrustedProxies          []string
 }
 
 // merge merges the given Config structs into the current Config.
@@ -71,6 +110,33 @@ func (c *Config) merge(configs ...*Config) *Config {
 		if cfg.NotFoundHandler != nil {
 			c.NotFoundHandler = cfg.NotFoundHandler
 		}
+		if cfg.MethodNotAllowedHandler != nil {
+			c.MethodNotAllowedHandler = cfg.MethodNotAllowedHandler
+		}
+		if cfg.ErrorHandler != nil {
+			c.ErrorHandler = cfg.ErrorHandler
+		}
+		if cfg.Validator != nil {
+			c.Validator = cfg.Validator
+		}
+		if cfg.DisableAutoHead {
+			c.DisableAutoHead = cfg.DisableAutoHead
+		}
+		if cfg.DisableAutoOptions {
+			c.DisableAutoOptions = cfg.DisableAutoOptions
+		}
+		if cfg.TrailingSlash != TrailingSlashIgnore {
+			c.TrailingSlash = cfg.TrailingSlash
+		}
+		if cfg.RedirectCleanPath {
+			c.RedirectCleanPath = cfg.RedirectCleanPath
+		}
+		if cfg.RedirectCaseInsensitive {
+			c.RedirectCaseInsensitive = cfg.RedirectCaseInsensitive
+		}
+		if cfg.DefaultVersion != "" {
+			c.DefaultVersion = cfg.DefaultVersion
+		}
 		if cfg.EnableDebug {
 			c.EnableDebug = cfg.EnableDebug
 		}
@@ -90,11 +156,14 @@ func (c *Config) merge(configs ...*Config) *Config {
 // defaultConfig returns a new Config with default values.
 func defaultConfig() *Config {
 	return &Config{
-		AppName:         "lightning-app",
-		JSONEncoder:     defaultJSONMarshal,
-		JSONDecoder:     defaultJSONUnmarshal,
-		NotFoundHandler: defaultNotFound,
-		EnableDebug:     false,
+		AppName:                 "lightning-app",
+		JSONEncoder:             defaultJSONMarshal,
+		JSONDecoder:             defaultJSONUnmarshal,
+		NotFoundHandler:         defaultNotFound,
+		MethodNotAllowedHandler: defaultMethodNotAllowed,
+		ErrorHandler:            defaultErrorHandler,
+		Validator:               validate,
+		EnableDebug:             false,
 	}
 }
 
@@ -115,15 +184,18 @@ func NewApp(c ...*Config) *Application {
 
 	app := &Application{
 		Config: config,
-		router: newRouter(),
 		Logger: lightlog.NewConsoleLogger(config.AppName, lightlog.TRACE),
 		contextPool: sync.Pool{
 			New: func() interface{} {
-				return &Context{index: -1}
+				return &Context{index: -1, data: contextData{}}
 			},
 		},
 	}
 	app.middlewares = make([]HandlerFunc, 0)
+	app.baseCtx, app.cancelBase = context.WithCancelCause(context.Background())
+	table := newRoutingTable()
+	table.router.strictSlash = config.TrailingSlash != TrailingSlashIgnore
+	app.table.Store(table)
 	app.parseTrustedProxies()
 
 	if app.Config.EnableDebug {
@@ -136,7 +208,7 @@ func NewApp(c ...*Config) *Application {
 					return
 				}
 			}
-			ctx.JSON(200, app.router.Roots)
+			ctx.JSON(200, app.Routes())
 		})
 	}
 
@@ -154,55 +226,172 @@ func DefaultApp() *Application {
 }
 
 // Use adds one or more Middlewares to the array of middlewares in the Application struct.
+// They run for all routes, including the routes added before, and for requests not matching
+// a route. Use panics once the Application serves requests, see RequestHandler.
 func (app *Application) Use(middlewares ...Middleware) {
+	app.mustNotServe("Use")
 	app.middlewares = append(app.middlewares, middlewares...)
 }
 
+// RegisterConstraint registers a named constraint that route patterns can apply to
+// parameters as `:param<name>`, including the routes of host Groups. It must be called
+// before the routes using it are added, and panics once the Application serves requests.
+func (app *Application) RegisterConstraint(name string, constraint ParamConstraint) {
+	app.mustNotServe("RegisterConstraint")
+	app.updateRoutes(func(t *routingTable) error {
+		t.router.constraints[name] = constraint
+		return nil
+	})
+}
+
 // AddRoute adds a new route to the router.
-// It composes the global middlewares, route-specific middlewares, and the actual handler function
-// to form a single MiddlewareFunc, and then adds it to the router.
-func (app *Application) AddRoute(method string, pattern string, handlers []HandlerFunc) {
+// The middlewares of the Application run before the given handlers, see Use.
+//
+// When several routes match a path, static segments take precedence over `:param` segments,
+// which take precedence over `*catchall` segments, regardless of the order of registration.
+// If the preferred branch fails to match the rest of the path, the next one is tried.
+//
+// The method may be any HTTP method, including extension methods such as "PROPFIND" and
+// "MKCOL", or MethodAny to match every method.
+//
+// AddRoute panics if the pattern conflicts with a route registered earlier for the same method,
+// either because it is the same pattern or because it only differs in parameter names.
+// It also panics if the method is not a valid HTTP method token, or if the options give the
+// route the name of another route.
+//
+// Parameters can be constrained with a registered constraint, e.g. `:id<int>`, or with a
+// regular expression, e.g. `:slug([a-z0-9-]+)`. Constrained parameters are tried in order
+// of registration before the unconstrained one, and only if the segment satisfies them.
+// Wildcards may start anywhere in a segment; literal colons and stars are written `::` and
+// `**`, e.g. `/v1/items::batchGet`.
+//
+// The options, such as WithName and WithMeta, are applied to the route once it is added.
+//
+// Routes can be added while the Application serves requests, which keep using the routes
+// they were matched against.
+func (app *Application) AddRoute(method string, pattern string, handlers []HandlerFunc, options ...RouteOption) *Route {
+	r, err := app.addRoute(method, pattern, handlers, nil, options)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// TryAddRoute adds a new route to the router like AddRoute, but returns a *RouteConflictError
+// instead of panicking if the pattern conflicts with an existing route, and an error if the
+// options name the route with the name of another route. The route is not added then.
+func (app *Application) TryAddRoute(method string, pattern string, handlers []HandlerFunc, options ...RouteOption) (*Route, error) {
+	return app.addRoute(method, pattern, handlers, nil, options)
+}
+
+// addRoute adds a new route to the router on behalf of the given Group, which may be nil,
+// and applies the options to it.
+func (app *Application) addRoute(method string, pattern string, handlers []HandlerFunc, group *Group, options []RouteOption) (*Route, error) {
 	app.Logger.Debug(" %s\t-> %s", method, pattern)
-	allHandlers := make([]HandlerFunc, 0)
-	allHandlers = append(allHandlers, app.middlewares...)
-	allHandlers = append(allHandlers, handlers...)
+	var r *Route
+	err := app.updateRoutes(func(t *routingTable) error {
+		host, version := "", ""
+		if group != nil {
+			host, version = group.getHost(), group.getVersion()
+		}
+
+		if name := optionsName(options); name != "" {
+			if err := t.checkName(nil, name); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		r, err = t.routerOf(host).addVersion(version).addRoute(method, pattern, handlers)
+		if err != nil {
+			return err
+		}
+		r.app = app
+		r.group = group
+		r.host = host
+		r.version = version
+		if app.serving.Load() {
+			r.chain = app.chain(group, handlers...)
+		}
+		t.applyOptions(r, options)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
 
-	app.router.addRoute(method, pattern, allHandlers)
+// RemoveRoute removes the route registered for the method and pattern, and reports whether
+// there was one. Routes can be removed while the Application serves requests, which keep
+// using the routes they were matched against.
+func (app *Application) RemoveRoute(method string, pattern string) bool {
+	return app.removeRoute("", "", method, pattern)
+}
+
+// removeRoute removes the route registered for the method and pattern for the given host
+// pattern and API version, which are empty for the routes registered without them.
+func (app *Application) removeRoute(host string, version string, method string, pattern string) bool {
+	var removed *Route
+	app.updateRoutes(func(t *routingTable) error {
+		removed = t.removeRoute(host, version, method, pattern)
+		return nil
+	})
+	if removed != nil {
+		app.Logger.Debug(" %s\t-x %s", method, pattern)
+	}
+	return removed != nil
 }
 
 // Get adds a new route with method "GET" to the router.
-func (app *Application) Get(pattern string, handlers ...HandlerFunc) {
-	app.AddRoute(MethodGet, pattern, handlers)
+func (app *Application) Get(pattern string, handlers ...HandlerFunc) *Route {
+	return app.AddRoute(MethodGet, pattern, handlers)
 }
 
 // Post adds a new route with method "POST" to the router.
-func (app *Application) Post(pattern string, handlers ...HandlerFunc) {
-	app.AddRoute(MethodPost, pattern, handlers)
+func (app *Application) Post(pattern string, handlers ...HandlerFunc) *Route {
+	return app.AddRoute(MethodPost, pattern, handlers)
 }
 
 // Put adds a new route with method "PUT" to the router.
-func (app *Application) Put(pattern string, handlers ...HandlerFunc) {
-	app.AddRoute(MethodPut, pattern, handlers)
+func (app *Application) Put(pattern string, handlers ...HandlerFunc) *Route {
+	return app.AddRoute(MethodPut, pattern, handlers)
 }
 
 // Delete adds a new route with method "DELETE" to the router.
-func (app *Application) Delete(pattern string, handlers ...HandlerFunc) {
-	app.AddRoute(MethodDelete, pattern, handlers)
+func (app *Application) Delete(pattern string, handlers ...HandlerFunc) *Route {
+	return app.AddRoute(MethodDelete, pattern, handlers)
 }
 
 // Head adds a new route with method "HEAD" to the router.
-func (app *Application) Head(pattern string, handlers ...HandlerFunc) {
-	app.AddRoute(MethodHead, pattern, handlers)
+func (app *Application) Head(pattern string, handlers ...HandlerFunc) *Route {
+	return app.AddRoute(MethodHead, pattern, handlers)
 }
 
 // Patch adds a new route with method "PATCH" to the router.
-func (app *Application) Patch(pattern string, handlers ...HandlerFunc) {
-	app.AddRoute(MethodPatch, pattern, handlers)
+func (app *Application) Patch(pattern string, handlers ...HandlerFunc) *Route {
+	return app.AddRoute(MethodPatch, pattern, handlers)
+}
+
+// Any adds a new route matching every method, including extension methods such as
+// "PROPFIND", to the router. Routes registered for the request's method take precedence
+// over routes added with Any.
+func (app *Application) Any(pattern string, handlers ...HandlerFunc) *Route {
+	return app.AddRoute(MethodAny, pattern, handlers)
+}
+
+// Match adds a new route for each of the given methods to the router.
+func (app *Application) Match(methods []string, pattern string, handlers ...HandlerFunc) []*Route {
+	routes := make([]*Route, 0, len(methods))
+	for _, method := range methods {
+		routes = append(routes, app.AddRoute(method, pattern, handlers))
+	}
+	return routes
 }
 
 // Options adds a new route with method "OPTIONS" to the router.
-func (app *Application) Options(pattern string, handlers ...HandlerFunc) {
-	app.AddRoute(MethodOptions, pattern, handlers)
+func (app *Application) Options(pattern string, handlers ...HandlerFunc) *Route {
+	return app.AddRoute(MethodOptions, pattern, handlers)
 }
 
 // Group returns a new instance of the Group struct with the given prefix.
@@ -258,43 +447,285 @@ func (app *Application) Static(root string, prefix string) {
 }
 
 // SetFuncMap sets the funcMap in the Application struct to the funcMap passed in as an argument.
+// It panics once the Application serves requests.
 func (app *Application) SetFuncMap(funcMap template.FuncMap) {
+	app.mustNotServe("SetFuncMap")
 	app.funcMap = funcMap
 }
 
 // LoadHTMLGlob loads HTML templates from a glob pattern and sets them in the Application struct.
 // It uses the template.Must function to panic if there is an error parsing the templates.
 // It also sets the funcMap in the Application struct to the funcMap passed in as an argument.
+// The templates can build the URL of named routes with the "url" function, e.g. {{ url "user" "id" 42 }}.
+// It panics once the Application serves requests.
 func (app *Application) LoadHTMLGlob(pattern string) {
-	app.htmlTemplates = template.Must(template.New("").Funcs(app.funcMap).ParseGlob(pattern))
+	app.mustNotServe("LoadHTMLGlob")
+	funcMap := template.FuncMap{"url": app.URL}
+	for name, fn := range app.funcMap {
+		funcMap[name] = fn
+	}
+	app.htmlTemplates = template.Must(template.New("").Funcs(funcMap).ParseGlob(pattern))
 }
 
 // RequestHandler returns a fasthttp.RequestHandler for the Application.
+//
+// The handler chains of the routes and of the requests not matching a route are compiled
+// once, with the middlewares registered so far, and the Application serves requests from
+// then on: Use, SetFuncMap, LoadHTMLGlob, RegisterConstraint and the Group settings panic,
+// and the Config must no longer be changed. Routes can still be added and removed, without
+// affecting requests in flight.
 func (app *Application) RequestHandler() fasthttp.RequestHandler {
+	app.build()
 	return func(ctx *fasthttp.RequestCtx) {
 		app.serveRequest(ctx)
 	}
 }
 
+// build applies the Config's TrailingSlash policy to the routes, compiles the handler chains
+// of the routes and of the requests not matching a route, marks the Application as serving
+// requests and builds the mounted Applications. Only the first call has an effect.
+func (app *Application) build() {
+	app.buildOnce.Do(func() {
+		app.tableMu.Lock()
+		defer app.tableMu.Unlock()
+
+		table := app.table.Load()
+		if err := table.setStrictSlash(app.Config.TrailingSlash != TrailingSlashIgnore); err != nil {
+			panic(err)
+		}
+		for _, r := range table.routes() {
+			r.chain = app.chain(r.group, r.handlers...)
+		}
+		app.notFound = app.chain(nil, app.Config.NotFoundHandler)
+		app.notAllowed = app.chain(nil, app.Config.MethodNotAllowedHandler)
+		app.autoOptions = app.chain(nil, defaultOptions)
+		app.serving.Store(true)
+
+		for _, child := range app.mounted {
+			child.build()
+		}
+	})
+}
+
+// chain returns a new handler chain running the middlewares of the Application, then those
+// of the Group and its ancestors if group is not nil, then the handlers.
+func (app *Application) chain(group *Group, handlers ...HandlerFunc) []HandlerFunc {
+	var groupMiddlewares []HandlerFunc
+	if group != nil {
+		groupMiddlewares = group.getMiddlewares()
+	}
+	chain := make([]HandlerFunc, 0, len(app.middlewares)+len(groupMiddlewares)+len(handlers))
+	chain = append(chain, app.middlewares...)
+	chain = append(chain, groupMiddlewares...)
+	return append(chain, handlers...)
+}
+
+// middlewareCount returns the number of middlewares running before the handlers of the
+// routes of the Group, which may be nil.
+func (app *Application) middlewareCount(group *Group) int {
+	n := len(app.middlewares)
+	for g := group; g != nil; g = g.parent {
+		n += len(g.middlewares)
+	}
+	return n
+}
+
+// mustNotServe panics if the Application serves requests, naming the method that can only
+// be called before.
+func (app *Application) mustNotServe(method string) {
+	if app.serving.Load() {
+		panic(fmt.Sprintf("%s called after the application started serving requests", method))
+	}
+}
+
 // serveRequest handles incoming HTTP requests by finding the matching route,
 // creating a new Context, setting the route parameters, and executing the middleware chain.
 func (app *Application) serveRequest(ctx *fasthttp.RequestCtx) {
+	app.build()
 	c := app.acquireContext(ctx)
 	defer app.releaseContext(c)
 
-	handlers, params := app.router.findRoute(c.Method, c.Path)
-
-	if handlers == nil {
-		handlers = append(app.middlewares, app.Config.NotFoundHandler)
-	}
-	c.setHandlers(handlers)
-	c.setParams(params)
+	c.setHandlers(app.matchRoute(c))
+	c.setParams(c.params)
 	c.setApp(app)
 
 	c.Next()
 	c.flush()
 }
 
+// matchRoute returns the handler chain for the request and stores its URL parameters in the Context.
+// The routes of the requested API version are tried before the routes registered without one,
+// and requests only matching the routes of other versions are answered with 406 Not Acceptable.
+// HEAD requests fall back to the matching GET route, with the response body discarded.
+// If no route matches, the request is redirected when the Config's redirect policies find a
+// route for the fixed path. Otherwise, if the path is registered for other methods, OPTIONS
+// requests are answered automatically and other requests by the MethodNotAllowedHandler, both
+// with an Allow header listing those methods. Otherwise the NotFoundHandler is used.
+func (app *Application) matchRoute(c *Context) []HandlerFunc {
+	rt := app.routes().routerFor(c)
+	routers := []*router{rt}
+	version := ""
+	if len(rt.versions) > 0 {
+		c.AddHeader(HeaderVary, HeaderAcceptVersion+", "+HeaderAccept)
+		version = app.requestVersion(c)
+		if v := rt.versions[version]; v != nil {
+			routers = []*router{v, rt}
+		}
+	}
+
+	path := c.Path
+	if app.Config.RedirectCleanPath {
+		path = cleanPath(c.Path)
+	}
+	for _, router := range routers {
+		r := app.lookupRoute(router, c.Method, path, &c.params)
+		if r == nil {
+			continue
+		}
+		if path != c.Path || app.Config.RedirectCleanPath && !isCleanPath(string(c.ctx.URI().PathOriginal())) {
+			c.params = c.params[:0]
+			return app.chain(nil, redirectTo(c, path))
+		}
+		if r.method == MethodGet && c.Method == MethodHead {
+			c.ctx.Response.SkipBody = true
+		}
+		if d := r.getDeprecation(); d != nil {
+			d.setHeaders(c)
+		}
+		c.route = r
+		return r.chain
+	}
+
+	if versions := app.otherVersions(rt, version, c); len(versions) > 0 {
+		return app.chain(nil, versionNotAcceptable(version, versions))
+	}
+
+	for _, router := range routers {
+		if fixed, ok := app.fixedPath(router, c, path); ok {
+			return app.chain(nil, redirectTo(c, fixed))
+		}
+	}
+
+	var routes []*Route
+	for _, router := range routers {
+		routes = append(routes, router.matchingRoutes(c.Path)...)
+	}
+	if len(routes) == 0 {
+		return app.notFound
+	}
+
+	allowed, autoOptions := app.allowedMethods(routes)
+	c.SetHeader(HeaderAllow, strings.Join(allowed, ", "))
+	if c.Method == MethodOptions && autoOptions {
+		return app.autoOptions
+	}
+	return app.notAllowed
+}
+
+// lookupRoute returns the route of the router matching the given method and path, appending
+// its URL parameters to params. HEAD requests fall back to the GET route unless disabled,
+// and all requests fall back to the routes added with Any.
+func (app *Application) lookupRoute(router *router, method string, path string, params *Params) *Route {
+	if r := router.getRoute(method, path, params); r != nil {
+		return r
+	}
+
+	if method == MethodHead && !app.Config.DisableAutoHead {
+		start := len(*params)
+		if r := router.getRoute(MethodGet, path, params); r != nil {
+			if r.autoHead() {
+				return r
+			}
+			*params = (*params)[:start]
+		}
+	}
+	return router.getRoute(MethodAny, path, params)
+}
+
+// fixedPath returns the path that the request is redirected to when its path, cleaned if
+// RedirectCleanPath is set, does not match a route: the path with or without the trailing
+// slash under TrailingSlashRedirect, or the path in the casing of a route's pattern if
+// RedirectCaseInsensitive is set.
+func (app *Application) fixedPath(router *router, c *Context, requestPath string) (string, bool) {
+	candidates := []string{requestPath}
+	if app.Config.TrailingSlash == TrailingSlashRedirect && requestPath != "/" {
+		path := strings.TrimSuffix(requestPath, "/")
+		if path == requestPath {
+			path += "/"
+		}
+		var params Params
+		if app.lookupRoute(router, c.Method, path, &params) != nil {
+			return path, true
+		}
+		candidates = append(candidates, path)
+	}
+
+	if !app.Config.RedirectCaseInsensitive {
+		return "", false
+	}
+	for _, path := range candidates {
+		if r, fixed := router.findCaseInsensitive(c.Method, path); r != nil {
+			return fixed, true
+		}
+		if c.Method == MethodHead && !app.Config.DisableAutoHead {
+			if r, fixed := router.findCaseInsensitive(MethodGet, path); r != nil && r.autoHead() {
+				return fixed, true
+			}
+		}
+		if r, fixed := router.findCaseInsensitive(MethodAny, path); r != nil {
+			return fixed, true
+		}
+	}
+	return "", false
+}
+
+// redirectTo returns a handler redirecting the request permanently to the given path,
+// relative to the mount prefix of the Application, keeping its query string. GET requests
+// are redirected with 301 Moved Permanently, and other requests with 308 Permanent Redirect
+// so that clients keep the method and body.
+func redirectTo(c *Context, path string) HandlerFunc {
+	code := StatusPermanentRedirect
+	if c.Method == MethodGet {
+		code = StatusMovedPermanently
+	}
+	location := (&url.URL{Path: c.mountPrefix + path, RawQuery: string(c.ctx.URI().QueryString())}).String()
+	return func(c *Context) {
+		c.Redirect(code, location)
+	}
+}
+
+// allowedMethods returns the sorted methods accepted by the given routes, including HEAD and
+// OPTIONS when they are answered automatically, and whether OPTIONS is answered automatically.
+func (app *Application) allowedMethods(routes []*Route) ([]string, bool) {
+	allowed := make([]string, 0, len(routes)+2)
+	hasHead, hasOptions := false, false
+	autoHead, autoOptions := false, false
+	for _, r := range routes {
+		allowed = append(allowed, r.method)
+		switch r.method {
+		case MethodHead:
+			hasHead = true
+		case MethodOptions:
+			hasOptions = true
+		case MethodGet:
+			autoHead = r.autoHead()
+		}
+		if r.autoOptions() {
+			autoOptions = true
+		}
+	}
+
+	if autoHead && !hasHead && !app.Config.DisableAutoHead {
+		allowed = append(allowed, MethodHead)
+	}
+	autoOptions = autoOptions && !hasOptions && !app.Config.DisableAutoOptions
+	if autoOptions {
+		allowed = append(allowed, MethodOptions)
+	}
+	sort.Strings(allowed)
+	return slices.Compact(allowed), autoOptions
+}
+
 // acquireContext gets a Context from the pool and initializes it.
 func (app *Application) acquireContext(ctx *fasthttp.RequestCtx) *Context {
 	c := app.contextPool.Get().(*Context)
@@ -305,7 +736,6 @@ func (app *Application) acquireContext(ctx *fasthttp.RequestCtx) *Context {
 	c.Method = c.req.method()
 	c.Path = c.req.path()
 	c.App = app
-	c.data = contextData{}
 
 	return c
 }
@@ -320,6 +750,9 @@ func (app *Application) releaseContext(c *Context) {
 func (app *Application) Run(address ...string) error {
 	addr := resolveAddress(address)
 	app.Logger.Info("Starting application on address `%s` 🚀🚀🚀", addr)
+	if app.Config.EnableDebug {
+		app.logRoutes()
+	}
 
 	app.mu.Lock()
 	app.server = &fasthttp.Server{
@@ -337,6 +770,9 @@ func (app *Application) Run(address ...string) error {
 func (app *Application) RunGraceful(shutdownTimeout int, address ...string) error {
 	addr := resolveAddress(address)
 	app.Logger.Info("Starting application on address `%s` 🚀🚀🚀", addr)
+	if app.Config.EnableDebug {
+		app.logRoutes()
+	}
 
 	app.mu.Lock()
 	app.server = &fasthttp.Server{
@@ -359,7 +795,7 @@ func (app *Application) RunGraceful(shutdownTimeout int, address ...string) erro
 		if shutdownTimeout <= 0 {
 			shutdownTimeout = 5
 		}
-		app.server.Shutdown()
+		app.Shutdown()
 		app.Logger.Info("Server stopped gracefully")
 		return nil
 
@@ -372,9 +808,15 @@ func (app *Application) RunGraceful(shutdownTimeout int, address ...string) erro
 }
 
 // Shutdown gracefully shuts down the server without interrupting active connections.
+// The contexts returned by Context.Context are cancelled with ErrServerShutdown, so that
+// the requests in flight can finish early.
 func (app *Application) Shutdown() {
-	if app.server != nil {
-		app.server.Shutdown()
+	app.cancelBase(ErrServerShutdown)
+	app.mu.Lock()
+	server := app.server
+	app.mu.Unlock()
+	if server != nil {
+		server.Shutdown()
 	}
 }
 
@@ -1,9 +1,11 @@
 package lightning
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"text/template"
 	"time"
@@ -53,7 +55,7 @@ func TestUse(t *testing.T) {
 func TestAddRoute(t *testing.T) {
 	app := NewApp()
 	app.AddRoute(MethodGet, "/test", []HandlerFunc{})
-	route, _ := app.router.findRoute(MethodGet, "/test")
+	route, _ := app.routes().router.findRoute(MethodGet, "/test")
 	if route == nil {
 		t.Errorf("Expected route to be added to router")
 	}
@@ -184,6 +186,528 @@ func TestNotFoundHandler(t *testing.T) {
 	}
 }
 
+func TestMethodNotAllowed(t *testing.T) {
+	app := NewApp()
+	app.Get("/test", func(c *Context) {
+		c.Text(StatusOK, "get")
+	})
+	app.Put("/test", func(c *Context) {
+		c.Text(StatusOK, "put")
+	})
+
+	ctx := createFasthttpRequest(MethodPost, "/test")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+	if allow := string(ctx.Response.Header.Peek(HeaderAllow)); allow != "GET, HEAD, OPTIONS, PUT" {
+		t.Errorf("Expected Allow header 'GET, HEAD, OPTIONS, PUT', got '%s'", allow)
+	}
+}
+
+func TestMethodNotAllowedCustomHandler(t *testing.T) {
+	app := NewApp(&Config{
+		MethodNotAllowedHandler: func(c *Context) {
+			c.JSONError(StatusMethodNotAllowed, "method not allowed")
+		},
+	})
+	called := false
+	app.Use(func(c *Context) {
+		called = true
+		c.Next()
+	})
+	app.Get("/users/:id", func(c *Context) {})
+
+	ctx := createFasthttpRequest(MethodDelete, "/users/42")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+	if !called {
+		t.Error("Expected global middleware to run for 405 responses")
+	}
+	if body := string(ctx.Response.Body()); body != `{"code":405,"message":"method not allowed"}` {
+		t.Errorf("Unexpected body: %s", body)
+	}
+}
+
+func TestNotFoundHasNoAllowHeader(t *testing.T) {
+	app := NewApp()
+	app.Get("/test", func(c *Context) {})
+
+	ctx := createFasthttpRequest(MethodPost, "/other")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusNotFound {
+		t.Errorf("Expected status %d, got %d", StatusNotFound, ctx.Response.StatusCode())
+	}
+	if allow := ctx.Response.Header.Peek(HeaderAllow); len(allow) != 0 {
+		t.Errorf("Expected no Allow header, got '%s'", allow)
+	}
+}
+
+func TestAutoHead(t *testing.T) {
+	app := NewApp()
+	app.Get("/test", func(c *Context) {
+		c.Text(StatusOK, "Hello, World!")
+	})
+
+	ctx := createFasthttpRequest(MethodHead, "/test")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusOK {
+		t.Errorf("Expected status %d, got %d", StatusOK, ctx.Response.StatusCode())
+	}
+	if !ctx.Response.SkipBody {
+		t.Error("Expected response body to be skipped")
+	}
+	raw := ctx.Response.String()
+	if !strings.Contains(raw, "Content-Length: 13") {
+		t.Errorf("Expected Content-Length of the GET body, got %q", raw)
+	}
+	if strings.Contains(raw, "Hello, World!") {
+		t.Errorf("Expected body to be discarded, got %q", raw)
+	}
+}
+
+func TestAutoHeadExplicitRoute(t *testing.T) {
+	app := NewApp()
+	app.Get("/test", func(c *Context) {
+		c.Text(StatusOK, "get")
+	})
+	app.Head("/test", func(c *Context) {
+		c.SetHeader("X-Head", "explicit")
+		c.SetStatus(StatusNoContent)
+	})
+
+	ctx := createFasthttpRequest(MethodHead, "/test")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusNoContent {
+		t.Errorf("Expected status %d, got %d", StatusNoContent, ctx.Response.StatusCode())
+	}
+	if string(ctx.Response.Header.Peek("X-Head")) != "explicit" {
+		t.Error("Expected explicit HEAD handler to be used")
+	}
+}
+
+func TestAutoHeadDisabled(t *testing.T) {
+	app := NewApp(&Config{DisableAutoHead: true})
+	app.Get("/test", func(c *Context) {
+		c.Text(StatusOK, "get")
+	})
+
+	ctx := createFasthttpRequest(MethodHead, "/test")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+	if allow := string(ctx.Response.Header.Peek(HeaderAllow)); allow != "GET, OPTIONS" {
+		t.Errorf("Expected Allow header 'GET, OPTIONS', got '%s'", allow)
+	}
+}
+
+func TestAutoOptions(t *testing.T) {
+	app := NewApp()
+	app.Use(func(c *Context) {
+		c.SetHeader("Access-Control-Allow-Origin", "*")
+		c.Next()
+	})
+	app.Get("/users/:id", func(c *Context) {})
+	app.Delete("/users/:id", func(c *Context) {})
+
+	ctx := createFasthttpRequest(MethodOptions, "/users/1")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusNoContent {
+		t.Errorf("Expected status %d, got %d", StatusNoContent, ctx.Response.StatusCode())
+	}
+	if allow := string(ctx.Response.Header.Peek(HeaderAllow)); allow != "DELETE, GET, HEAD, OPTIONS" {
+		t.Errorf("Expected Allow header 'DELETE, GET, HEAD, OPTIONS', got '%s'", allow)
+	}
+	if string(ctx.Response.Header.Peek("Access-Control-Allow-Origin")) != "*" {
+		t.Error("Expected global middleware to run for automatic OPTIONS responses")
+	}
+}
+
+func TestAutoOptionsDisabled(t *testing.T) {
+	app := NewApp(&Config{DisableAutoOptions: true})
+	app.Get("/test", func(c *Context) {})
+
+	ctx := createFasthttpRequest(MethodOptions, "/test")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+	if allow := string(ctx.Response.Header.Peek(HeaderAllow)); allow != "GET, HEAD" {
+		t.Errorf("Expected Allow header 'GET, HEAD', got '%s'", allow)
+	}
+}
+
+func TestAutoOptionsNotFound(t *testing.T) {
+	app := NewApp()
+	app.Get("/test", func(c *Context) {})
+
+	ctx := createFasthttpRequest(MethodOptions, "/missing")
+	app.serveRequest(ctx)
+
+	if ctx.Response.StatusCode() != StatusNotFound {
+		t.Errorf("Expected status %d, got %d", StatusNotFound, ctx.Response.StatusCode())
+	}
+}
+
+func TestMixedSegmentParams(t *testing.T) {
+	app := NewApp()
+	app.Get("/files/:name.:ext", func(c *Context) {
+		c.Text(StatusOK, c.Param("name")+"|"+c.Param("ext"))
+	})
+	app.Get("/repos/*path/raw", func(c *Context) {
+		c.Text(StatusOK, c.Param("path"))
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/files/archive.tar.gz", "archive.tar|gz"},
+		{"/repos/go-labx/lightning/raw", "go-labx/lightning"},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodGet, tt.path)
+		app.serveRequest(ctx)
+		if got := string(ctx.Response.Body()); got != tt.want {
+			t.Errorf("Expected body '%s' for '%s', got '%s'", tt.want, tt.path, got)
+		}
+	}
+}
+
+func TestTrailingSlashIgnore(t *testing.T) {
+	app := NewApp()
+	app.Get("/users", func(c *Context) {
+		c.Text(StatusOK, "users")
+	})
+
+	for _, path := range []string{"/users", "/users/"} {
+		ctx := createFasthttpRequest(MethodGet, path)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != StatusOK {
+			t.Errorf("Expected status %d for '%s', got %d", StatusOK, path, ctx.Response.StatusCode())
+		}
+	}
+}
+
+func TestTrailingSlashStrict(t *testing.T) {
+	app := NewApp(&Config{TrailingSlash: TrailingSlashStrict})
+	app.Get("/users", func(c *Context) {
+		c.Text(StatusOK, "users")
+	})
+	app.Get("/docs/", func(c *Context) {
+		c.Text(StatusOK, "docs")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/users", StatusOK},
+		{"/users/", StatusNotFound},
+		{"/docs/", StatusOK},
+		{"/docs", StatusNotFound},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodGet, tt.path)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("Expected status %d for '%s', got %d", tt.status, tt.path, ctx.Response.StatusCode())
+		}
+	}
+}
+
+func TestRedirectPolicies(t *testing.T) {
+	app := NewApp(&Config{
+		TrailingSlash:           TrailingSlashRedirect,
+		RedirectCleanPath:       true,
+		RedirectCaseInsensitive: true,
+	})
+	handler := func(c *Context) {
+		c.Text(StatusOK, "ok")
+	}
+	app.Get("/users", handler)
+	app.Post("/users", handler)
+	app.Get("/docs/", handler)
+	app.Get("/users/:name/Profile", handler)
+
+	tests := []struct {
+		method   string
+		path     string
+		status   int
+		location string
+	}{
+		{MethodGet, "/users", StatusOK, ""},
+		{MethodGet, "/users/", StatusMovedPermanently, "/users"},
+		{MethodPost, "/users/", StatusPermanentRedirect, "/users"},
+		{MethodGet, "/docs", StatusMovedPermanently, "/docs/"},
+		{MethodGet, "/docs?page=2", StatusMovedPermanently, "/docs/?page=2"},
+		{MethodGet, "/USERS", StatusMovedPermanently, "/users"},
+		{MethodGet, "/Users/", StatusMovedPermanently, "/users"},
+		{MethodGet, "/USERS/Jane/profile", StatusMovedPermanently, "/users/Jane/Profile"},
+		{MethodHead, "/Users", StatusPermanentRedirect, "/users"},
+		{MethodGet, "//a/../users", StatusMovedPermanently, "/users"},
+		{MethodGet, "/a/../users/", StatusMovedPermanently, "/users"},
+		{MethodGet, "/docs/./", StatusMovedPermanently, "/docs/"},
+		{MethodGet, "/missing/", StatusNotFound, ""},
+		{MethodDelete, "/users", StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(tt.method, tt.path)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("Expected status %d for %s '%s', got %d", tt.status, tt.method, tt.path, ctx.Response.StatusCode())
+			continue
+		}
+		location := string(ctx.Response.Header.Peek(HeaderLocation))
+		if !strings.HasSuffix(location, tt.location) || (tt.location == "") != (location == "") {
+			t.Errorf("Expected Location '%s' for %s '%s', got '%s'", tt.location, tt.method, tt.path, location)
+		}
+	}
+}
+
+func TestRedirectCleanPathUnnormalized(t *testing.T) {
+	app := NewApp(&Config{RedirectCleanPath: true})
+	app.Get("/users/:id", func(c *Context) {
+		c.Text(StatusOK, c.Param("id"))
+	})
+
+	app.build()
+
+	// fasthttp normalizes the path of requests, so the path is set on the Context instead.
+	for path, location := range map[string]string{"/a/../users/1": "/users/1", "/users//./1": "/users/1"} {
+		ctx := createFasthttpRequest(MethodGet, "/users/1")
+		c := app.acquireContext(ctx)
+		c.Path = path
+		c.setHandlers(app.matchRoute(c))
+		c.Next()
+		c.flush()
+		app.releaseContext(c)
+		if ctx.Response.StatusCode() != StatusMovedPermanently {
+			t.Errorf("Expected status %d for '%s', got %d", StatusMovedPermanently, path, ctx.Response.StatusCode())
+		}
+		if got := string(ctx.Response.Header.Peek(HeaderLocation)); !strings.HasSuffix(got, location) {
+			t.Errorf("Expected Location '%s' for '%s', got '%s'", location, path, got)
+		}
+	}
+}
+
+func TestTrailingSlashSetAfterNewApp(t *testing.T) {
+	app := NewApp()
+	app.Get("/u/", func(c *Context) {
+		c.Text(StatusOK, "u")
+	})
+	app.Config.TrailingSlash = TrailingSlashStrict
+
+	ctx := createFasthttpRequest(MethodGet, "/u")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusNotFound {
+		t.Errorf("Expected status %d for '/u', got %d", StatusNotFound, ctx.Response.StatusCode())
+	}
+	ctx = createFasthttpRequest(MethodGet, "/u/")
+	app.serveRequest(ctx)
+	if body := string(ctx.Response.Body()); body != "u" {
+		t.Errorf("Expected body 'u' for '/u/', got '%s'", body)
+	}
+}
+
+func TestRedirectPoliciesDisabled(t *testing.T) {
+	app := NewApp(&Config{TrailingSlash: TrailingSlashStrict})
+	app.Get("/users", func(c *Context) {
+		c.Text(StatusOK, "users")
+	})
+
+	for _, path := range []string{"/USERS", "/users/"} {
+		ctx := createFasthttpRequest(MethodGet, path)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != StatusNotFound {
+			t.Errorf("Expected status %d for '%s', got %d", StatusNotFound, path, ctx.Response.StatusCode())
+		}
+	}
+
+	ctx := createFasthttpRequest(MethodGet, "//a/../users")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusOK {
+		t.Errorf("Expected cleaned path to be served with status %d, got %d", StatusOK, ctx.Response.StatusCode())
+	}
+}
+
+func TestCustomMethod(t *testing.T) {
+	app := NewApp()
+	app.AddRoute("PROPFIND", "/dav/*path", []HandlerFunc{func(c *Context) {
+		c.Text(207, "propfind "+c.Param("path"))
+	}})
+	app.AddRoute("MKCOL", "/dav/*path", []HandlerFunc{func(c *Context) {
+		c.SetStatus(StatusCreated)
+	}})
+
+	ctx := createFasthttpRequest("PROPFIND", "/dav/docs/a.txt")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != 207 || string(ctx.Response.Body()) != "propfind docs/a.txt" {
+		t.Errorf("Expected PROPFIND route, got %d '%s'", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+
+	ctx = createFasthttpRequest(MethodGet, "/dav/docs")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+	if allow := string(ctx.Response.Header.Peek(HeaderAllow)); allow != "MKCOL, OPTIONS, PROPFIND" {
+		t.Errorf("Expected Allow 'MKCOL, OPTIONS, PROPFIND', got '%s'", allow)
+	}
+}
+
+func TestInvalidMethodPanics(t *testing.T) {
+	for _, method := range []string{"", "GET POST", "GET\n", "(GET)"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for method %q", method)
+				}
+			}()
+			NewApp().AddRoute(method, "/", []HandlerFunc{func(c *Context) {}})
+		}()
+	}
+}
+
+func TestAny(t *testing.T) {
+	app := NewApp()
+	app.Any("/proxy/*path", func(c *Context) {
+		c.Text(StatusOK, "any "+c.Method)
+	})
+	app.Get("/proxy/health", func(c *Context) {
+		c.Text(StatusOK, "health")
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{MethodGet, "/proxy/users", "any GET"},
+		{MethodPost, "/proxy/users", "any POST"},
+		{"PROPFIND", "/proxy/users", "any PROPFIND"},
+		{MethodOptions, "/proxy/users", "any OPTIONS"},
+		{MethodGet, "/proxy/health", "health"},
+		{MethodPost, "/proxy/health", "any POST"},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(tt.method, tt.path)
+		app.serveRequest(ctx)
+		if body := string(ctx.Response.Body()); body != tt.body {
+			t.Errorf("Expected '%s' for %s %s, got '%s'", tt.body, tt.method, tt.path, body)
+		}
+	}
+
+	ctx := createFasthttpRequest(MethodHead, "/proxy/health")
+	app.serveRequest(ctx)
+	if !ctx.Response.SkipBody {
+		t.Error("Expected HEAD to be answered by the GET route")
+	}
+
+	ctx = createFasthttpRequest(MethodGet, "/proxy/users")
+	app.serveRequest(ctx)
+	if ctx.Response.SkipBody {
+		t.Error("Expected the body of the Any route to be kept")
+	}
+
+	routes := app.Routes()
+	if routes[0].Method != MethodAny {
+		t.Errorf("Expected method '%s' in route info, got '%s'", MethodAny, routes[0].Method)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	app := NewApp()
+	routes := app.Match([]string{MethodGet, MethodPost}, "/form", func(c *Context) {
+		c.Text(StatusOK, c.Method)
+	})
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, got %d", len(routes))
+	}
+
+	for _, method := range []string{MethodGet, MethodPost} {
+		ctx := createFasthttpRequest(method, "/form")
+		app.serveRequest(ctx)
+		if body := string(ctx.Response.Body()); body != method {
+			t.Errorf("Expected '%s', got '%s'", method, body)
+		}
+	}
+
+	ctx := createFasthttpRequest(MethodPut, "/form")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+}
+
+func TestRegisterConstraint(t *testing.T) {
+	app := NewApp()
+	app.RegisterConstraint("lower", func(value string) bool {
+		return strings.ToLower(value) == value
+	})
+	app.Get("/tags/:tag<lower>", func(c *Context) {
+		c.Text(StatusOK, c.Param("tag"))
+	})
+
+	ctx := createFasthttpRequest(MethodGet, "/tags/golang")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusOK || string(ctx.Response.Body()) != "golang" {
+		t.Errorf("Expected 200 'golang', got %d '%s'", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+
+	ctx = createFasthttpRequest(MethodGet, "/tags/GoLang")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusNotFound {
+		t.Errorf("Expected status %d, got %d", StatusNotFound, ctx.Response.StatusCode())
+	}
+}
+
+func TestAddRouteConflictPanics(t *testing.T) {
+	app := NewApp()
+	app.Get("/users/:id", func(c *Context) {})
+
+	defer func() {
+		r := recover()
+		err, ok := r.(*RouteConflictError)
+		if !ok {
+			t.Fatalf("Expected *RouteConflictError panic, got %v", r)
+		}
+		msg := err.Error()
+		if !strings.Contains(msg, "/users/:id") || !strings.Contains(msg, "/users/:name") {
+			t.Errorf("Expected both patterns in message, got '%s'", msg)
+		}
+		if strings.Count(msg, "lightning_test.go:") != 2 {
+			t.Errorf("Expected both call sites in message, got '%s'", msg)
+		}
+	}()
+	app.Get("/users/:name", func(c *Context) {})
+}
+
+func TestTryAddRoute(t *testing.T) {
+	app := NewApp()
+	if _, err := app.TryAddRoute(MethodGet, "/test", []HandlerFunc{func(c *Context) {}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	r, err := app.TryAddRoute(MethodGet, "/test", []HandlerFunc{func(c *Context) {}})
+	if err == nil || r != nil {
+		t.Fatal("Expected conflict error for duplicate route")
+	}
+	if !strings.HasPrefix(err.Error(), "duplicate route GET /test") {
+		t.Errorf("Unexpected error message: %s", err)
+	}
+}
+
 func TestMiddlewareExecution(t *testing.T) {
 	app := NewApp()
 	order := []int{}
@@ -363,6 +887,39 @@ func TestConfigMergeNotFoundHandler(t *testing.T) {
 	}
 }
 
+func TestConfigMergeMethodNotAllowedHandler(t *testing.T) {
+	c := defaultConfig()
+	handler := func(ctx *Context) {}
+	c.merge(&Config{MethodNotAllowedHandler: handler})
+	if c.MethodNotAllowedHandler == nil {
+		t.Error("Expected MethodNotAllowedHandler to be set")
+	}
+}
+
+func TestConfigMergeDisableAutoHeadOptions(t *testing.T) {
+	c := defaultConfig()
+	c.merge(&Config{DisableAutoHead: true, DisableAutoOptions: true})
+	if !c.DisableAutoHead || !c.DisableAutoOptions {
+		t.Error("Expected DisableAutoHead and DisableAutoOptions to be set")
+	}
+}
+
+func TestConfigMergeRedirectPolicies(t *testing.T) {
+	c := defaultConfig()
+	c.merge(&Config{TrailingSlash: TrailingSlashRedirect, RedirectCleanPath: true, RedirectCaseInsensitive: true})
+	if c.TrailingSlash != TrailingSlashRedirect {
+		t.Errorf("Expected TrailingSlashRedirect, got %d", c.TrailingSlash)
+	}
+	if !c.RedirectCleanPath || !c.RedirectCaseInsensitive {
+		t.Error("Expected RedirectCleanPath and RedirectCaseInsensitive to be set")
+	}
+
+	c.merge(&Config{})
+	if c.TrailingSlash != TrailingSlashRedirect {
+		t.Errorf("Expected TrailingSlash to be kept, got %d", c.TrailingSlash)
+	}
+}
+
 func TestConfigMergeMaxRequestBodySize(t *testing.T) {
 	cfg := &Config{}
 	merged := cfg.merge(&Config{MaxRequestBodySize: 4096})
@@ -710,6 +1267,21 @@ func TestRouterSearchMultipleParams(t *testing.T) {
 	}
 }
 
+func TestRouterMatchingRoutes(t *testing.T) {
+	router := newRouter()
+	router.addRoute(MethodPost, "/users/:id", []HandlerFunc{func(c *Context) {}})
+	router.addRoute(MethodGet, "/users/:id", []HandlerFunc{func(c *Context) {}})
+	router.addRoute(MethodDelete, "/posts/:id", []HandlerFunc{func(c *Context) {}})
+
+	routes := router.matchingRoutes("/users/1")
+	if len(routes) != 2 || routes[0].method != MethodGet || routes[1].method != MethodPost {
+		t.Errorf("Expected GET and POST routes, got %v", routes)
+	}
+	if routes := router.matchingRoutes("/comments/1"); len(routes) != 0 {
+		t.Errorf("Expected no routes, got %v", routes)
+	}
+}
+
 func TestLogger(t *testing.T) {
 	app := NewApp()
 	if app.Logger == nil {
@@ -843,6 +1415,21 @@ func TestDefaultNotFoundHandler(t *testing.T) {
 	}
 }
 
+func TestDefaultMethodNotAllowedHandler(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+	c := &Context{
+		ctx:   ctx,
+		index: -1,
+		res:   newResponse(ctx),
+	}
+	defaultMethodNotAllowed(c)
+	c.flush()
+
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+}
+
 func TestDefaultInternalServerErrorHandler(t *testing.T) {
 	ctx := &fasthttp.RequestCtx{}
 	ctx.Request.Header.SetMethod("GET")
@@ -868,120 +1455,6 @@ func TestNewRouter(t *testing.T) {
 	}
 }
 
-func TestNodeMatchChild(t *testing.T) {
-	n := &node{
-		Children: map[string]*node{
-			"foo": {Part: "foo"},
-		},
-	}
-
-	child := n.matchChild("foo")
-	if child == nil {
-		t.Error("Expected child node")
-	}
-
-	child = n.matchChild("bar")
-	if child != nil {
-		t.Error("Expected nil for non-existent child")
-	}
-}
-
-func TestNodeInsert(t *testing.T) {
-	root := &node{}
-	root.insert("/a/b/c", []string{"a", "b", "c"}, 0, []HandlerFunc{func(c *Context) {}})
-
-	if root.Children == nil {
-		t.Fatal("Expected children to be initialized")
-	}
-
-	n := root.matchChild("a")
-	if n == nil {
-		t.Fatal("Expected node 'a'")
-	}
-	if n.Part != "a" {
-		t.Errorf("Expected part 'a', got '%s'", n.Part)
-	}
-}
-
-func TestNodeInsertWildParam(t *testing.T) {
-	root := &node{}
-	root.insert("/users/:id", []string{"users", ":id"}, 0, []HandlerFunc{func(c *Context) {}})
-
-	users := root.matchChild("users")
-	if users == nil {
-		t.Fatal("Expected 'users' node")
-	}
-
-	idNode := users.matchChild(":id")
-	if idNode == nil {
-		t.Fatal("Expected ':id' node")
-	}
-	if !idNode.IsWild {
-		t.Error("Expected :id to be wild")
-	}
-}
-
-func TestNodeSearch(t *testing.T) {
-	root := &node{}
-	root.insert("/a/b", []string{"a", "b"}, 0, []HandlerFunc{func(c *Context) {}})
-
-	n := root.search([]string{"a", "b"}, 0)
-	if n == nil {
-		t.Error("Expected to find node")
-	}
-	if n.Pattern != "/a/b" {
-		t.Errorf("Expected pattern '/a/b', got '%s'", n.Pattern)
-	}
-}
-
-func TestNodeSearchNotFound(t *testing.T) {
-	root := &node{}
-	root.insert("/a/b", []string{"a", "b"}, 0, []HandlerFunc{func(c *Context) {}})
-
-	n := root.search([]string{"a", "c"}, 0)
-	if n != nil {
-		t.Error("Expected nil for non-existent path")
-	}
-}
-
-func TestNodeSearchWild(t *testing.T) {
-	root := &node{}
-	root.insert("/api/*", []string{"api", "*"}, 0, []HandlerFunc{func(c *Context) {}})
-
-	n := root.search([]string{"api", "users", "123"}, 0)
-	if n == nil {
-		t.Error("Expected to find wildcard node")
-	}
-}
-
-func TestNodeSearchParam(t *testing.T) {
-	root := &node{}
-	root.insert("/users/:id", []string{"users", ":id"}, 0, []HandlerFunc{func(c *Context) {}})
-
-	n := root.search([]string{"users", "42"}, 0)
-	if n == nil {
-		t.Error("Expected to find param node")
-	}
-}
-
-func TestNodeSearchEmptyParts(t *testing.T) {
-	root := &node{Pattern: "/"}
-	n := root.search([]string{}, 0)
-	if n == nil {
-		t.Error("Expected to find root node")
-	}
-}
-
-func TestNodeSearchWildReturnNil(t *testing.T) {
-	root := &node{}
-	root.insert("/api/*", []string{"api", "*"}, 0, []HandlerFunc{func(c *Context) {}})
-
-	n := root.search([]string{"api"}, 0)
-	if n != nil {
-		t.Error("Expected nil for incomplete path")
-	}
-}
-
 func TestResponseFlushWithRedirect(t *testing.T) {
 	resp, ctx := createResponse()
 
@@ -1447,3 +1920,105 @@ func TestRemoteAddrWithoutTrustedProxies(t *testing.T) {
 		t.Error("Expected X-Real-IP to be ignored when no TrustedProxies configured")
 	}
 }
+
+func TestUseAfterRoutes(t *testing.T) {
+	app := NewApp()
+	app.Get("/ping", func(c *Context) {
+		c.Text(StatusOK, "pong")
+	})
+	api := app.Group("/api")
+	api.Get("/users", func(c *Context) {
+		c.Text(StatusOK, "users")
+	})
+	app.Use(func(c *Context) {
+		c.SetHeader("X-App", "true")
+		c.Next()
+	})
+	api.Use(func(c *Context) {
+		c.SetHeader("X-API", "true")
+		c.Next()
+	})
+	handler := app.RequestHandler()
+
+	tests := []struct {
+		path string
+		app  string
+		api  string
+	}{
+		{"/ping", "true", ""},
+		{"/api/users", "true", "true"},
+		{"/missing", "true", ""},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodGet, tt.path)
+		handler(ctx)
+		if got := string(ctx.Response.Header.Peek("X-App")); got != tt.app {
+			t.Errorf("Expected X-App '%s' for '%s', got '%s'", tt.app, tt.path, got)
+		}
+		if got := string(ctx.Response.Header.Peek("X-API")); got != tt.api {
+			t.Errorf("Expected X-API '%s' for '%s', got '%s'", tt.api, tt.path, got)
+		}
+	}
+
+	app.Get("/late", func(c *Context) {})
+	ctx := createFasthttpRequest(MethodGet, "/late")
+	handler(ctx)
+	if string(ctx.Response.Header.Peek("X-App")) != "true" {
+		t.Error("Expected middlewares to run for a route added while serving")
+	}
+	if routes := app.Routes(); routes[0].Middlewares != 2 || routes[2].Middlewares != 1 {
+		t.Errorf("Expected middleware counts 2 and 1, got %+v", routes)
+	}
+}
+
+func TestMutationWhileServingPanics(t *testing.T) {
+	app := NewApp()
+	group := app.Group("/api")
+	app.RequestHandler()
+
+	mutations := map[string]func(){
+		"Use":                      func() { app.Use(func(c *Context) {}) },
+		"SetFuncMap":               func() { app.SetFuncMap(nil) },
+		"LoadHTMLGlob":             func() { app.LoadHTMLGlob("*.html") },
+		"RegisterConstraint":       func() { app.RegisterConstraint("hex", nil) },
+		"Group.Use":                func() { group.Use(func(c *Context) {}) },
+		"Group.DisableAutoHead":    func() { group.DisableAutoHead() },
+		"Group.DisableAutoOptions": func() { group.DisableAutoOptions() },
+	}
+	for name, mutate := range mutations {
+		func() {
+			defer func() {
+				if r := recover(); r == nil || !strings.HasPrefix(fmt.Sprint(r), name+" called after") {
+					t.Errorf("Expected %s to panic, got %v", name, r)
+				}
+			}()
+			mutate()
+		}()
+	}
+}
+
+func TestNotFoundWhileServingConcurrently(t *testing.T) {
+	app := NewApp()
+	for i := 0; i < 3; i++ {
+		app.Use(func(c *Context) { c.Next() })
+	}
+	app.Post("/items", func(c *Context) {})
+	handler := app.RequestHandler()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			method := MethodGet
+			if i%2 == 0 {
+				method = MethodPut
+			}
+			for j := 0; j < 100; j++ {
+				handler(createFasthttpRequest(method, "/items"))
+				handler(createFasthttpRequest(method, "/missing"))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
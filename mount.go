@@ -0,0 +1,96 @@
+package lightning
+
+import "strings"
+
+// MountOptions holds the options for mounting an Application under a prefix.
+type MountOptions struct {
+	// StripPrefix removes the prefix from Context.Path for the child's handlers.
+	StripPrefix bool
+}
+
+// Mount dispatches requests whose path is the prefix or starts with it to the child
+// Application. The child matches its routes against the rest of the path and runs them with
+// its own middlewares, and answers misses with its own NotFoundHandler, after the
+// middlewares of the Application.
+//
+// Routes of the Application matching the request's method take precedence over the mount,
+// which is registered with Any. The prefix may contain parameters, which remain available to
+// the child's handlers.
+//
+// The child starts serving requests along with the Application, see RequestHandler, or right
+// away if the Application already serves requests. Until then, middlewares and routes can
+// still be added to it.
+func (app *Application) Mount(prefix string, child *Application, options ...*MountOptions) {
+	opts := &MountOptions{}
+	for _, o := range options {
+		if o != nil {
+			*opts = *o
+		}
+	}
+
+	app.tableMu.Lock()
+	serving := app.serving.Load()
+	if !serving {
+		app.mounted = append(app.mounted, child)
+	}
+	app.tableMu.Unlock()
+	if serving {
+		child.build()
+	}
+	handler := func(c *Context) {
+		child.serveMounted(c, opts.StripPrefix)
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	patterns := []string{prefix + "/*"}
+	switch {
+	case prefix == "":
+		patterns = append(patterns, "/")
+	case app.Config.TrailingSlash != TrailingSlashIgnore:
+		patterns = append(patterns, prefix, prefix+"/")
+	default:
+		patterns = append(patterns, prefix)
+	}
+	for _, pattern := range patterns {
+		app.Any(pattern, handler)
+	}
+}
+
+// serveMounted runs the child Application's handlers for the request within the Context of
+// the parent, whose unnamed catch-all parameter holds the rest of the path. Aborting the
+// child's handler chain also aborts the parent's.
+func (child *Application) serveMounted(c *Context, stripPrefix bool) {
+	path, params, route, app, handlers, index, mountPrefix := c.Path, c.params, c.route, c.App, c.handlers, c.index, c.mountPrefix
+
+	rest := "/"
+	if n := len(c.params); n > 0 && c.params[n-1].Key == "" {
+		rest += c.params[n-1].Value
+		c.params = c.params[:n-1]
+	}
+	if strings.HasSuffix(path, "/") && !strings.HasSuffix(rest, "/") {
+		rest += "/"
+	}
+	if rest == "/" {
+		c.mountPrefix += strings.TrimSuffix(path, "/")
+	} else {
+		c.mountPrefix += strings.TrimSuffix(path, rest)
+	}
+
+	c.Path = rest
+	c.route = nil
+	c.setHandlers(child.matchRoute(c))
+	c.setParams(c.params)
+	c.setApp(child)
+	c.index = -1
+	if !stripPrefix {
+		c.Path = path
+	}
+
+	c.Next()
+
+	if c.IsAborted() {
+		index = abortIndex
+	}
+	c.Path, c.params, c.route, c.App, c.handlers, c.index, c.mountPrefix = path, params, route, app, handlers, index, mountPrefix
+	c.setParams(c.params)
+}
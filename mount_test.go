@@ -0,0 +1,211 @@
+package lightning
+
+import (
+	"strings"
+	"testing"
+)
+
+func newAdminApp() *Application {
+	admin := NewApp(&Config{
+		NotFoundHandler: func(c *Context) {
+			c.Text(StatusNotFound, "admin not found")
+		},
+	})
+	admin.Use(func(c *Context) {
+		c.SetHeader("X-Admin", "true")
+		c.Next()
+	})
+	admin.Get("/", func(c *Context) {
+		c.Text(StatusOK, "dashboard "+c.Path)
+	})
+	admin.Get("/users/:id", func(c *Context) {
+		c.Text(StatusOK, "user "+c.Param("id")+" "+c.Path)
+	})
+	return admin
+}
+
+func TestMount(t *testing.T) {
+	app := NewApp()
+	app.Use(func(c *Context) {
+		c.SetHeader("X-Parent", "true")
+		c.Next()
+	})
+	app.Mount("/admin", newAdminApp())
+	app.Get("/admin/health", func(c *Context) {
+		c.Text(StatusOK, "parent health")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/admin", StatusOK, "dashboard /admin"},
+		{"/admin/", StatusOK, "dashboard /admin/"},
+		{"/admin/users/42", StatusOK, "user 42 /admin/users/42"},
+		{"/admin/missing", StatusNotFound, "admin not found"},
+		{"/admin/health", StatusOK, "parent health"},
+		{"/administrator", StatusNotFound, "Not Found"},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodGet, tt.path)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("Expected status %d for '%s', got %d", tt.status, tt.path, ctx.Response.StatusCode())
+		}
+		if body := string(ctx.Response.Body()); !strings.HasPrefix(body, tt.body) {
+			t.Errorf("Expected body '%s' for '%s', got '%s'", tt.body, tt.path, body)
+		}
+		if string(ctx.Response.Header.Peek("X-Parent")) != "true" {
+			t.Errorf("Expected parent middleware to run for '%s'", tt.path)
+		}
+	}
+
+	ctx := createFasthttpRequest(MethodGet, "/admin/users/1")
+	app.serveRequest(ctx)
+	if string(ctx.Response.Header.Peek("X-Admin")) != "true" {
+		t.Error("Expected child middleware to run")
+	}
+}
+
+func TestMountStripPrefix(t *testing.T) {
+	app := NewApp()
+	app.Mount("/admin/", newAdminApp(), &MountOptions{StripPrefix: true})
+
+	tests := map[string]string{
+		"/admin":          "dashboard /",
+		"/admin/users/42": "user 42 /users/42",
+	}
+	for path, want := range tests {
+		ctx := createFasthttpRequest(MethodGet, path)
+		app.serveRequest(ctx)
+		if body := string(ctx.Response.Body()); body != want {
+			t.Errorf("Expected body '%s' for '%s', got '%s'", want, path, body)
+		}
+	}
+}
+
+func TestMountPrefixParams(t *testing.T) {
+	child := NewApp()
+	child.Get("/users/:id", func(c *Context) {
+		c.Text(StatusOK, c.Param("tenant")+"|"+c.Param("id"))
+	})
+
+	app := NewApp()
+	app.Mount("/tenants/:tenant", child)
+
+	ctx := createFasthttpRequest(MethodGet, "/tenants/acme/users/7")
+	app.serveRequest(ctx)
+	if body := string(ctx.Response.Body()); body != "acme|7" {
+		t.Errorf("Expected body 'acme|7', got '%s'", body)
+	}
+}
+
+func TestMountMethodNotAllowedAndRedirects(t *testing.T) {
+	child := NewApp(&Config{TrailingSlash: TrailingSlashRedirect})
+	child.Post("/items", func(c *Context) {})
+	child.Get("/docs/", func(c *Context) {})
+
+	app := NewApp()
+	app.Mount("/api", child)
+
+	ctx := createFasthttpRequest(MethodGet, "/api/items")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+
+	ctx = createFasthttpRequest(MethodGet, "/api/docs")
+	app.serveRequest(ctx)
+	if location := string(ctx.Response.Header.Peek(HeaderLocation)); !strings.HasSuffix(location, "/api/docs/") {
+		t.Errorf("Expected redirect to '/api/docs/', got '%s'", location)
+	}
+}
+
+func TestMountRoot(t *testing.T) {
+	app := NewApp()
+	app.Get("/ping", func(c *Context) {
+		c.Text(StatusOK, "pong")
+	})
+	app.Mount("/", newAdminApp())
+
+	tests := map[string]string{
+		"/ping":     "pong",
+		"/":         "dashboard /",
+		"/users/42": "user 42 /users/42",
+	}
+	for path, want := range tests {
+		ctx := createFasthttpRequest(MethodGet, path)
+		app.serveRequest(ctx)
+		if body := string(ctx.Response.Body()); body != want {
+			t.Errorf("Expected body '%s' for '%s', got '%s'", want, path, body)
+		}
+	}
+}
+
+func TestMountAbort(t *testing.T) {
+	child := NewApp()
+	child.Use(func(c *Context) {
+		c.AbortWithStatus(StatusUnauthorized)
+	})
+	child.Get("/", func(c *Context) {})
+
+	aborted := false
+	app := NewApp()
+	app.Use(func(c *Context) {
+		c.Next()
+		aborted = c.IsAborted()
+	})
+	app.Mount("/admin", child)
+
+	ctx := createFasthttpRequest(MethodGet, "/admin")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", StatusUnauthorized, ctx.Response.StatusCode())
+	}
+	if !aborted {
+		t.Error("Expected the abort of the child to abort the parent")
+	}
+}
+
+func TestMountBeforeChildMiddlewares(t *testing.T) {
+	app := NewApp()
+	admin := NewApp()
+	admin.Get("/users", func(c *Context) {
+		c.Text(StatusOK, "users")
+	})
+	app.Mount("/admin", admin)
+	admin.Use(func(c *Context) {
+		c.SetHeader("X-Auth", "checked")
+		c.Next()
+	})
+
+	ctx := createFasthttpRequest(MethodGet, "/admin/users")
+	app.serveRequest(ctx)
+	if string(ctx.Response.Header.Peek("X-Auth")) != "checked" {
+		t.Error("Expected the middleware added to the child after mounting it to run")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Use to panic once the parent serves requests")
+		}
+	}()
+	admin.Use(func(c *Context) {})
+}
+
+func TestMountWhileServing(t *testing.T) {
+	app := NewApp()
+	handler := app.RequestHandler()
+	admin := NewApp()
+	admin.Get("/users", func(c *Context) {
+		c.Text(StatusOK, "users")
+	})
+	app.Mount("/admin", admin)
+
+	ctx := createFasthttpRequest(MethodGet, "/admin/users")
+	handler(ctx)
+	if body := string(ctx.Response.Body()); body != "users" {
+		t.Errorf("Expected body 'users', got '%s'", body)
+	}
+}
@@ -0,0 +1,267 @@
+package lightning
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/valyala/fasthttp"
+)
+
+// defaultMaxMemory is the default UploadLimits.MaxMemory.
+const defaultMaxMemory = 32 << 20
+
+var fileHeaderType = reflect.TypeFor[*multipart.FileHeader]()
+
+// UploadLimits restricts the multipart forms a route accepts, see WithUploadLimits.
+type UploadLimits struct {
+	// MaxMemory is the number of bytes of the files of a form copied in memory when it is
+	// parsed, beyond which they are copied to temporary files, which are removed when the
+	// request is done. It defaults to 32 MB. The request body itself is read in memory, up
+	// to the Config's MaxRequestBodySize.
+	MaxMemory int64
+	// MaxFiles is the maximum number of files of a form, or unlimited if zero.
+	MaxFiles int
+	// MaxFileSize is the maximum size of each file in bytes, or unlimited if zero.
+	MaxFileSize int64
+	// AllowedTypes are the media types files may have, as detected from their content, such
+	// as "image/png", or "image/*" for any image. Files of any type are allowed if it is empty.
+	AllowedTypes []string
+}
+
+// WithUploadLimits returns a RouteOption that restricts the multipart forms the route
+// accepts. Forms exceeding MaxFiles or MaxFileSize are rejected with 413 Request Entity Too
+// Large, and files of a type that is not allowed with 415 Unsupported Media Type. The limits
+// are checked while the parts of the form are read one at a time, so that rejected forms
+// are neither read past the first file exceeding them nor copied.
+func WithUploadLimits(limits UploadLimits) RouteOption {
+	return func(r *Route) {
+		r.uploadLimits = &limits
+	}
+}
+
+// limited reports whether the limits restrict the files of a form.
+func (l *UploadLimits) limited() bool {
+	return l.MaxFiles > 0 || l.MaxFileSize > 0 || len(l.AllowedTypes) > 0
+}
+
+// check reads the parts of the form one at a time without copying them, and returns an
+// *HTTPError as soon as the form exceeds the limits.
+func (l *UploadLimits) check(r *multipart.Reader) error {
+	files := 0
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return NewHTTPError(StatusBadRequest).WithInternal(err)
+		}
+		name := part.FormName()
+		if name == "" || part.FileName() == "" {
+			continue
+		}
+
+		files++
+		if l.MaxFiles > 0 && files > l.MaxFiles {
+			return NewHTTPError(StatusRequestEntityTooLarge, fmt.Sprintf("too many files, the limit is %d", l.MaxFiles))
+		}
+		src := &countingReader{r: part}
+		if l.MaxFileSize > 0 {
+			src.r = io.LimitReader(part, l.MaxFileSize+1)
+		}
+		if len(l.AllowedTypes) > 0 {
+			m, err := mimetype.DetectReader(src)
+			if err != nil {
+				return NewHTTPError(StatusBadRequest).WithInternal(err)
+			}
+			if !allowedType(m.String(), l.AllowedTypes) {
+				return NewHTTPError(StatusUnsupportedMediaType, fmt.Sprintf("file '%s' has the unsupported type %s", name, m.String()))
+			}
+		}
+		if _, err := io.Copy(io.Discard, src); err != nil {
+			return NewHTTPError(StatusBadRequest).WithInternal(err)
+		}
+		if l.MaxFileSize > 0 && src.n > l.MaxFileSize {
+			return NewHTTPError(StatusRequestEntityTooLarge, fmt.Sprintf("file '%s' is too large, the limit is %d bytes", name, l.MaxFileSize))
+		}
+	}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// allowedType reports whether the media type matches one of the allowed types or ranges.
+func allowedType(contentType string, allowed []string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	for _, a := range allowed {
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+		} else if mimetype.EqualsAny(mediaType, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectContentType returns the media type of the uploaded file detected from its content,
+// regardless of the name and Content-Type the client sent, e.g. "image/png" or
+// "text/plain; charset=utf-8".
+func DetectContentType(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	m, err := mimetype.DetectReader(f)
+	if err != nil {
+		return "", err
+	}
+	return m.String(), nil
+}
+
+// MultipartForm parses the multipart form of the request, with the UploadLimits of the
+// matched route, which are checked before the files are copied. Files beyond MaxMemory are
+// copied to temporary files, which are removed when the request is done, so the form must
+// not be used after the handler chain returns. The form is parsed once per request.
+//
+// It returns a 415 Unsupported Media Type *HTTPError if the request is not a multipart
+// form, a 400 Bad Request *HTTPError if it is malformed, and the *HTTPError of the limits
+// it exceeds.
+func (c *Context) MultipartForm() (*multipart.Form, error) {
+	if c.multipartForm != nil {
+		return c.multipartForm, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(c.ContentType())
+	if err != nil || mediaType != MIMEMultipartForm {
+		return nil, NewHTTPError(StatusUnsupportedMediaType)
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, NewHTTPError(StatusBadRequest, "missing multipart boundary")
+	}
+
+	limits := &UploadLimits{}
+	if c.route != nil && c.route.uploadLimits != nil {
+		limits = c.route.uploadLimits
+	}
+	maxMemory := limits.MaxMemory
+	if maxMemory <= 0 {
+		maxMemory = defaultMaxMemory
+	}
+
+	body := c.RawBody()
+	if limits.limited() {
+		if err := limits.check(multipart.NewReader(bytes.NewReader(body), boundary)); err != nil {
+			return nil, err
+		}
+	}
+	form, err := multipart.NewReader(bytes.NewReader(body), boundary).ReadForm(maxMemory)
+	if err != nil {
+		return nil, NewHTTPError(StatusBadRequest).WithInternal(err)
+	}
+	c.multipartForm = form
+	return form, nil
+}
+
+// FormValue returns the first value of the form field with the given name, from either a
+// URL-encoded or a multipart form body, or an empty string if there is none. Unlike Query,
+// it does not return query parameters.
+func (c *Context) FormValue(name string) string {
+	if value := c.ctx.PostArgs().Peek(name); value != nil {
+		return string(value)
+	}
+	if !strings.HasPrefix(c.ContentType(), MIMEMultipartForm) {
+		return ""
+	}
+	form, err := c.MultipartForm()
+	if err != nil || len(form.Value[name]) == 0 {
+		return ""
+	}
+	return form.Value[name][0]
+}
+
+// FormFile returns the first file uploaded with the given field name in a multipart form.
+// It returns the errors of MultipartForm, and a 400 Bad Request *HTTPError if the form has
+// no such file.
+func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	if len(form.File[name]) == 0 {
+		return nil, NewHTTPError(StatusBadRequest, fmt.Sprintf("missing file '%s'", name))
+	}
+	return form.File[name][0], nil
+}
+
+// SaveUploadedFile saves the uploaded file to dst, creating its directory if needed.
+//
+// WARNING: The file name sent by the client, fh.Filename, must not be used in dst without
+// sanitizing it, e.g. with filepath.Base, as it may contain path separators.
+func (c *Context) SaveUploadedFile(fh *multipart.FileHeader, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return err
+	}
+	return fasthttp.SaveMultipartFile(fh, dst)
+}
+
+// releaseMultipartForm removes the temporary files of the multipart form of the request.
+func (c *Context) releaseMultipartForm() {
+	if c.multipartForm != nil {
+		c.multipartForm.RemoveAll()
+		c.multipartForm = nil
+	}
+}
+
+// bindFiles sets the exported *multipart.FileHeader and []*multipart.FileHeader fields of
+// the struct v that are tagged with `form` to the files uploaded with the field name.
+func bindFiles(v reflect.Value, files map[string][]*multipart.FileHeader) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup("form")
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				bindFiles(v.Field(i), files)
+			}
+			continue
+		}
+		name, _, _ = strings.Cut(name, ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if len(files[name]) == 0 {
+			continue
+		}
+
+		switch field.Type {
+		case fileHeaderType:
+			v.Field(i).Set(reflect.ValueOf(files[name][0]))
+		case reflect.SliceOf(fileHeaderType):
+			v.Field(i).Set(reflect.ValueOf(files[name]))
+		}
+	}
+}
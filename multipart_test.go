@@ -0,0 +1,213 @@
+package lightning
+
+import (
+	"bytes"
+	"errors"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// pngHeader is the start of a PNG file, enough to detect its type.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+type testFile struct {
+	field   string
+	name    string
+	content []byte
+}
+
+// createMultipartBody returns a multipart form body with the fields and files, and its
+// content type.
+func createMultipartBody(t *testing.T, fields map[string]string, files ...testFile) ([]byte, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		if err := w.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range files {
+		part, err := w.CreateFormFile(f.field, f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(f.content)
+	}
+	w.Close()
+	return buf.Bytes(), w.FormDataContentType()
+}
+
+func TestContext_FormValue(t *testing.T) {
+	c, ctx := createTestContext(MethodPost, "/form?name=query", []byte("name=urlencoded&tag=a"))
+	ctx.Request.Header.SetContentType(MIMEApplicationForm)
+	if value := c.FormValue("name"); value != "urlencoded" {
+		t.Errorf("Expected 'urlencoded', got '%s'", value)
+	}
+
+	body, contentType := createMultipartBody(t, map[string]string{"name": "multipart"})
+	c, ctx = createTestContext(MethodPost, "/form?name=query", body)
+	ctx.Request.Header.SetContentType(contentType)
+	if value := c.FormValue("name"); value != "multipart" {
+		t.Errorf("Expected 'multipart', got '%s'", value)
+	}
+	if value := c.FormValue("missing"); value != "" {
+		t.Errorf("Expected an empty value, got '%s'", value)
+	}
+
+	c, _ = createTestContext(MethodGet, "/form?name=query", nil)
+	if value := c.FormValue("name"); value != "" {
+		t.Errorf("Expected query parameters to be ignored, got '%s'", value)
+	}
+}
+
+func TestContext_FormFileAndSaveUploadedFile(t *testing.T) {
+	body, contentType := createMultipartBody(t, nil, testFile{"avatar", "avatar.png", pngHeader})
+	c, ctx := createTestContext(MethodPost, "/upload", body)
+	ctx.Request.Header.SetContentType(contentType)
+
+	fh, err := c.FormFile("avatar")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if fh.Filename != "avatar.png" || fh.Size != int64(len(pngHeader)) {
+		t.Errorf("Expected avatar.png of %d bytes, got %s of %d bytes", len(pngHeader), fh.Filename, fh.Size)
+	}
+	if contentType, err := DetectContentType(fh); err != nil || contentType != "image/png" {
+		t.Errorf("Expected image/png, got '%s' and %v", contentType, err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "uploads", filepath.Base(fh.Filename))
+	if err := c.SaveUploadedFile(fh, dst); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved, err := os.ReadFile(dst); err != nil || !bytes.Equal(saved, pngHeader) {
+		t.Errorf("Expected the file to be saved, got %v", err)
+	}
+
+	var httpErr *HTTPError
+	if _, err := c.FormFile("missing"); !errors.As(err, &httpErr) || httpErr.Status != StatusBadRequest {
+		t.Errorf("Expected a 400 HTTPError for a missing file, got %v", err)
+	}
+	c, _ = createTestContext(MethodPost, "/upload", []byte(`{}`))
+	if _, err := c.FormFile("avatar"); !errors.As(err, &httpErr) || httpErr.Status != StatusUnsupportedMediaType {
+		t.Errorf("Expected a 415 HTTPError for a JSON body, got %v", err)
+	}
+}
+
+func TestUploadLimits(t *testing.T) {
+	app := NewApp()
+	app.Post("/avatars", HandleErr(func(c *Context) error {
+		if _, err := c.MultipartForm(); err != nil {
+			return err
+		}
+		c.Text(StatusOK, "ok")
+		return nil
+	})).With(WithUploadLimits(UploadLimits{
+		MaxFiles:     2,
+		MaxFileSize:  64,
+		AllowedTypes: []string{"image/*", "application/pdf"},
+	}))
+
+	tests := []struct {
+		name   string
+		files  []testFile
+		status int
+	}{
+		{"allowed", []testFile{{"a", "a.png", pngHeader}, {"b", "b.pdf", []byte("%PDF-1.4\n")}}, StatusOK},
+		{"too many", []testFile{{"a", "a.png", pngHeader}, {"a", "b.png", pngHeader}, {"c", "c.png", pngHeader}}, StatusRequestEntityTooLarge},
+		{"too large", []testFile{{"a", "a.png", append(pngHeader, make([]byte, 64)...)}}, StatusRequestEntityTooLarge},
+		{"type", []testFile{{"a", "a.png", []byte("#!/bin/sh\necho")}}, StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		body, contentType := createMultipartBody(t, nil, tt.files...)
+		ctx := createFasthttpRequest(MethodPost, "/avatars")
+		ctx.Request.Header.SetContentType(contentType)
+		ctx.Request.SetBody(body)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("%s: Expected status %d, got %d: %s", tt.name, tt.status, ctx.Response.StatusCode(), ctx.Response.Body())
+		}
+	}
+}
+
+func TestUploadLimitsStopReading(t *testing.T) {
+	limits := &UploadLimits{MaxFiles: 1, MaxFileSize: 64}
+	body, contentType := createMultipartBody(t, nil, testFile{"a", "a.png", pngHeader}, testFile{"b", "b.png", pngHeader})
+	_, params, _ := mime.ParseMediaType(contentType)
+	truncated := body[:len(body)-len(params["boundary"])-8]
+
+	var httpErr *HTTPError
+	err := limits.check(multipart.NewReader(bytes.NewReader(truncated), params["boundary"]))
+	if !errors.As(err, &httpErr) || httpErr.Status != StatusRequestEntityTooLarge {
+		t.Errorf("Expected a 413 HTTPError before the end of the form, got %v", err)
+	}
+
+	limits = &UploadLimits{MaxFileSize: 8}
+	body, contentType = createMultipartBody(t, nil, testFile{"a", "a.png", pngHeader})
+	_, params, _ = mime.ParseMediaType(contentType)
+	truncated = body[:len(body)-len(params["boundary"])-8]
+	err = limits.check(multipart.NewReader(bytes.NewReader(truncated), params["boundary"]))
+	if !errors.As(err, &httpErr) || httpErr.Status != StatusRequestEntityTooLarge {
+		t.Errorf("Expected a 413 HTTPError before the end of the file, got %v", err)
+	}
+}
+
+func TestMultipartFormTempFilesRemoved(t *testing.T) {
+	app := NewApp()
+	var tmpFile string
+	app.Post("/upload", func(c *Context) {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		f, err := fh.Open()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		defer f.Close()
+		if osFile, ok := f.(*os.File); ok {
+			tmpFile = osFile.Name()
+		}
+		c.Text(StatusOK, "ok")
+	}).With(WithUploadLimits(UploadLimits{MaxMemory: 1}))
+
+	body, contentType := createMultipartBody(t, nil, testFile{"file", "large.bin", bytes.Repeat([]byte("x"), 1024)})
+	ctx := createFasthttpRequest(MethodPost, "/upload")
+	ctx.Request.Header.SetContentType(contentType)
+	ctx.Request.SetBody(body)
+	app.serveRequest(ctx)
+
+	if tmpFile == "" {
+		t.Fatal("Expected the file to be stored in a temporary file")
+	}
+	if _, err := os.Stat(tmpFile); !os.IsNotExist(err) {
+		t.Errorf("Expected the temporary file to be removed after the request, got %v", err)
+	}
+}
+
+func TestBindMultipartFiles(t *testing.T) {
+	type upload struct {
+		Title  string                  `form:"title" validate:"required"`
+		Cover  *multipart.FileHeader   `form:"cover"`
+		Photos []*multipart.FileHeader `form:"photo"`
+	}
+
+	body, contentType := createMultipartBody(t, map[string]string{"title": "trip"},
+		testFile{"cover", "cover.png", pngHeader},
+		testFile{"photo", "1.png", pngHeader},
+		testFile{"photo", "2.png", pngHeader})
+	c, ctx := createTestContext(MethodPost, "/uploads", body)
+	ctx.Request.Header.SetContentType(contentType)
+
+	var u upload
+	if err := c.Bind(&u); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if u.Title != "trip" || u.Cover == nil || u.Cover.Filename != "cover.png" || len(u.Photos) != 2 {
+		t.Errorf("Expected the title, cover and 2 photos to be bound, got %+v", u)
+	}
+	c.reset()
+}
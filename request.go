@@ -8,27 +8,26 @@ import (
 
 type request struct {
 	ctx        *fasthttp.RequestCtx
-	pathParams map[string]string
+	pathParams Params
 	app        *Application
 }
 
 func newRequest(ctx *fasthttp.RequestCtx) *request {
 	return &request{
-		ctx:        ctx,
-		pathParams: make(map[string]string),
+		ctx: ctx,
 	}
 }
 
-func (r *request) setParams(params map[string]string) {
+func (r *request) setParams(params Params) {
 	r.pathParams = params
 }
 
 func (r *request) param(key string) string {
-	return r.pathParams[key]
+	return r.pathParams.Get(key)
 }
 
 func (r *request) params() map[string]string {
-	return r.pathParams
+	return r.pathParams.toMap()
 }
 
 func (r *request) query(key string) string {
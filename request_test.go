@@ -91,7 +91,7 @@ func TestRequest_Param(t *testing.T) {
 	ctx.Request.Header.SetRequestURI("/test")
 
 	r := newRequest(ctx)
-	r.setParams(map[string]string{"param1": "value1"})
+	r.setParams(Params{{Key: "param1", Value: "value1"}})
 
 	if got := r.param("param1"); got != "value1" {
 		t.Errorf("param() = %v, want %v", got, "value1")
@@ -108,13 +108,13 @@ func TestRequest_Params(t *testing.T) {
 	ctx.Request.Header.SetRequestURI("/test")
 
 	r := newRequest(ctx)
-	params := map[string]string{"param1": "value1", "param2": "value2"}
+	params := Params{{Key: "param1", Value: "value1"}, {Key: "param2", Value: "value2"}}
 	r.setParams(params)
 
 	got := r.params()
-	for k, v := range params {
-		if got[k] != v {
-			t.Errorf("params()[%s] = %v, want %v", k, got[k], v)
+	for _, p := range params {
+		if got[p.Key] != p.Value {
+			t.Errorf("params()[%s] = %v, want %v", p.Key, got[p.Key], p.Value)
 		}
 	}
 }
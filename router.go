@@ -1,114 +1,833 @@
 package lightning
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 )
 
+// Route is a handler chain registered for a method and pattern.
+type Route struct {
+	method       string
+	pattern      string
+	name         string
+	handlers     []HandlerFunc
+	chain        []HandlerFunc
+	meta         map[string]any
+	app          *Application
+	group        *Group
+	host         string
+	version      string
+	deprecation  *deprecation
+	uploadLimits *UploadLimits
+	paramKeys    []string
+	source       string
+
+	// origin is the route as it was added, shared by the copies updating it.
+	origin *Route
+
+	// request and response are the types bound and rendered by a handler of Handle.
+	request  reflect.Type
+	response reflect.Type
+}
+
+// RouteOption configures a Route at registration, see Application.AddRoute and Route.With.
+type RouteOption func(*Route)
+
+// WithName returns a RouteOption that names the route, which Application.URL and
+// Context.URLFor use to build its URL. Applying it panics if the name is already used by
+// another route.
+func WithName(name string) RouteOption {
+	return func(r *Route) {
+		r.name = name
+	}
+}
+
+// WithMeta returns a RouteOption that attaches the value to the route under the given key,
+// such as the scopes required to access it or its rate-limit class.
+func WithMeta(key string, value any) RouteOption {
+	return func(r *Route) {
+		if r.meta == nil {
+			r.meta = make(map[string]any)
+		}
+		r.meta[key] = value
+	}
+}
+
+// RouteConflictError is returned when a route is registered for a method and path that are
+// already matched by a route with the same or an equivalent pattern, such as `/a/:id` and `/a/:name`.
+type RouteConflictError struct {
+	Method          string
+	Pattern         string
+	Source          string
+	ExistingPattern string
+	ExistingSource  string
+}
+
+func (e *RouteConflictError) Error() string {
+	kind := "ambiguous"
+	if e.Pattern == e.ExistingPattern {
+		kind = "duplicate"
+	}
+	return fmt.Sprintf("%s route %s %s (registered at %s) conflicts with %s %s (registered at %s)",
+		kind, e.Method, e.Pattern, e.Source, e.Method, e.ExistingPattern, e.ExistingSource)
+}
+
+// With applies the given options to the route.
+//
+// Once the Application serves requests, routes are not modified, so that requests in flight
+// are not affected: With stores an updated copy of the route and returns it instead. The
+// options are applied to the copy in use even if r was replaced by such a copy, but With
+// panics if the route was removed.
+func (r *Route) With(options ...RouteOption) *Route {
+	return r.app.updateRoute(r, func(t *routingTable, r *Route) {
+		t.applyOptions(r, options)
+	})
+}
+
+// Method returns the method the route is registered for, or MethodAny.
+func (r *Route) Method() string {
+	return r.method
+}
+
+// Pattern returns the pattern the route is registered with, including the prefix of its Group.
+func (r *Route) Pattern() string {
+	return r.pattern
+}
+
+// Name returns the name of the route, or an empty string if it has none.
+func (r *Route) Name() string {
+	return r.name
+}
+
+// Meta returns the metadata attached to the route under the given key, and whether it exists.
+func (r *Route) Meta(key string) (any, bool) {
+	value, ok := r.meta[key]
+	return value, ok
+}
+
+// RequestType returns the type the handler of the route binds requests to, as recorded with
+// WithTypes, or nil if it has none.
+func (r *Route) RequestType() reflect.Type {
+	return r.request
+}
+
+// ResponseType returns the type the handler of the route renders, as recorded with
+// WithTypes, or nil if it has none.
+func (r *Route) ResponseType() reflect.Type {
+	return r.response
+}
+
+// autoHead reports whether HEAD requests may be answered by this GET route.
+func (r *Route) autoHead() bool {
+	return r.group == nil || !r.group.autoHeadDisabled()
+}
+
+// autoOptions reports whether OPTIONS requests may be answered automatically for this route's path.
+func (r *Route) autoOptions() bool {
+	return r.group == nil || !r.group.autoOptionsDisabled()
+}
+
+// Param is a single URL parameter, consisting of a key and a value.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params is a list of URL parameters in the order they appear in the route pattern.
+type Params []Param
+
+// Get returns the value of the first parameter with the given key, or an empty string.
+func (ps Params) Get(key string) string {
+	for i := range ps {
+		if ps[i].Key == key {
+			return ps[i].Value
+		}
+	}
+	return ""
+}
+
+// toMap returns the named parameters as a map.
+func (ps Params) toMap() map[string]string {
+	values := make(map[string]string, len(ps))
+	for _, p := range ps {
+		if p.Key != "" {
+			values[p.Key] = p.Value
+		}
+	}
+	return values
+}
+
+// nodeKind is the kind of path segment a node matches.
+type nodeKind uint8
+
+const (
+	staticNode nodeKind = iota
+	paramNode
+	catchAllNode
+)
+
+// node is a node of the radix tree used to match request paths.
+// Static children are stored with compressed prefixes and indexed by their first byte,
+// while the parameter and catch-all children are kept apart so that lookups try them
+// only after the static children failed to match. A wildcard node is inSegment if it has
+// static children continuing the same path segment, as in `/files/:name.:ext`.
 type node struct {
-	Pattern  string           `json:"pattern"`
-	Part     string           `json:"part"`
-	IsWild   bool             `json:"isWild"`
-	Children map[string]*node `json:"children,omitempty"`
-	handlers []HandlerFunc
+	prefix        string
+	children      []*node
+	paramChildren []*node
+	catchAll      *node
+	kind          nodeKind
+	indices       string
+	inSegment     bool
+	constraint    ParamConstraint
+	route         *Route
 }
 
-func (n *node) matchChild(part string) *node {
-	return n.Children[part]
+// insert adds the route to the tree under the given canonical pattern, resolving
+// parameter constraints by name from constraints. If a route is already stored where the
+// pattern ends, it is left in place and returned instead.
+func (n *node) insert(pattern string, r *Route, constraints map[string]ParamConstraint) *Route {
+	for _, token := range patternTokens(pattern) {
+		switch token.kind {
+		case paramNode:
+			n = n.insertParam(token.text, constraints)
+		case catchAllNode:
+			if n.catchAll == nil {
+				n.catchAll = &node{prefix: token.text, kind: catchAllNode}
+			}
+			n = n.catchAll
+		default:
+			n = n.insertStatic(token.text)
+		}
+	}
+	if n.route != nil {
+		return n.route
+	}
+	n.route = r
+	return nil
 }
 
-func (n *node) insert(pattern string, parts []string, height int, handlers []HandlerFunc) {
-	if len(parts) == height {
-		n.Pattern = pattern
-		n.handlers = handlers
-		return
+// insertParam returns the parameter child for the given segment, creating it if needed.
+// Parameter children with the same constraint are shared regardless of the parameter name.
+// Constrained children are kept before the unconstrained one so that they are tried first.
+func (n *node) insertParam(segment string, constraints map[string]ParamConstraint) *node {
+	_, constraint := splitParam(segment)
+	for _, child := range n.paramChildren {
+		if _, c := splitParam(child.prefix); c == constraint {
+			return child
+		}
 	}
 
-	part := parts[height]
-	if n.Children == nil {
-		n.Children = make(map[string]*node)
+	child := &node{prefix: segment, kind: paramNode, constraint: compileConstraint(constraint, constraints)}
+	last := len(n.paramChildren)
+	if constraint != "" && last > 0 && n.paramChildren[last-1].constraint == nil {
+		last--
 	}
-	child, exists := n.Children[part]
-	if !exists {
-		child = &node{Part: part, IsWild: part[0] == ':' || part[0] == '*'}
-		n.Children[part] = child
+	n.paramChildren = append(n.paramChildren, nil)
+	copy(n.paramChildren[last+1:], n.paramChildren[last:])
+	n.paramChildren[last] = child
+	return child
+}
+
+// insertStatic adds a static path below the node, splitting existing children where their
+// prefixes diverge, and returns the node at which the path ends.
+func (n *node) insertStatic(path string) *node {
+	for {
+		i := strings.IndexByte(n.indices, path[0])
+		if i < 0 {
+			child := &node{prefix: path, kind: staticNode}
+			n.indices += path[:1]
+			n.children = append(n.children, child)
+			if path[0] != '/' {
+				n.inSegment = true
+			}
+			return child
+		}
+
+		child := n.children[i]
+		l := commonPrefixLength(path, child.prefix)
+		if l < len(child.prefix) {
+			split := *child
+			split.prefix = child.prefix[l:]
+			*child = node{
+				prefix:   child.prefix[:l],
+				children: []*node{&split},
+				kind:     staticNode,
+				indices:  split.prefix[:1],
+			}
+		}
+
+		path = path[l:]
+		if len(path) == 0 {
+			return child
+		}
+		n = child
 	}
-	child.insert(pattern, parts, height+1, handlers)
 }
 
-func (n *node) search(parts []string, height int) *node {
-	if len(parts) == height {
-		if n.Pattern != "" {
-			return n
+// search returns the route matching the remaining path below the node, appending the values
+// of the matched parameters to params. Static children take precedence over the parameter
+// children, which take precedence over the catch-all child; a failed branch is backtracked.
+// A parameter child is only tried if the segment satisfies its constraint.
+func (n *node) search(path string, params *Params) *Route {
+	if len(path) == 0 {
+		return n.route
+	}
+
+	if i := strings.IndexByte(n.indices, path[0]); i >= 0 {
+		child := n.children[i]
+		if strings.HasPrefix(path, child.prefix) {
+			if r := child.search(path[len(child.prefix):], params); r != nil {
+				return r
+			}
 		}
-		return nil
 	}
 
-	if n.IsWild && n.Pattern != "" {
-		return n
+	if len(n.paramChildren) > 0 {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		for _, child := range n.paramChildren {
+			if r := child.searchParam(path, end, params); r != nil {
+				return r
+			}
+		}
 	}
 
-	part := parts[height]
-	child := n.matchChild(part)
+	if n.catchAll != nil {
+		return n.catchAll.searchCatchAll(path, params)
+	}
+
+	return nil
+}
 
-	if child != nil {
-		if nextNode := child.search(parts, height+1); nextNode != nil {
-			return nextNode
+// searchParam matches the parameter node against the segment ending at end. If the node has
+// static children within the segment, the value may end before it at the last position where
+// they match, which is tried first; otherwise the value is the rest of the segment.
+func (n *node) searchParam(path string, end int, params *Params) *Route {
+	if n.inSegment {
+		for i := end - 1; i > 0; i-- {
+			if strings.IndexByte(n.indices, path[i]) < 0 {
+				continue
+			}
+			if r := n.searchValue(path, i, params); r != nil {
+				return r
+			}
 		}
 	}
+	if end == 0 {
+		return nil
+	}
+	return n.searchValue(path, end, params)
+}
 
-	for _, child := range n.Children {
-		if child.IsWild {
-			if nextNode := child.search(parts, height+1); nextNode != nil {
-				return nextNode
+// searchCatchAll matches the catch-all node against the rest of the path. If the node has
+// static children, the value may end before the end of the path at the last position where
+// they match, which is tried first; otherwise the value is the rest of the path.
+func (n *node) searchCatchAll(path string, params *Params) *Route {
+	if len(n.children) > 0 {
+		for i := len(path) - 1; i > 0; i-- {
+			if strings.IndexByte(n.indices, path[i]) < 0 {
+				continue
+			}
+			if r := n.searchValue(path, i, params); r != nil {
+				return r
 			}
 		}
 	}
+	if n.route == nil {
+		return nil
+	}
+	*params = append(*params, Param{Value: path})
+	return n.route
+}
 
+// searchValue matches the wildcard node with path[:end] as its value and the rest of the path
+// below it, restoring params if that fails.
+func (n *node) searchValue(path string, end int, params *Params) *Route {
+	value := path[:end]
+	if n.constraint != nil && !n.constraint(value) {
+		return nil
+	}
+	*params = append(*params, Param{Value: value})
+	if r := n.search(path[end:], params); r != nil {
+		return r
+	}
+	*params = (*params)[:len(*params)-1]
 	return nil
 }
 
+// routes appends the routes stored in the node and its descendants to routes.
+func (n *node) routes(routes []*Route) []*Route {
+	if n.route != nil {
+		routes = append(routes, n.route)
+	}
+	for _, child := range n.children {
+		routes = child.routes(routes)
+	}
+	for _, child := range n.paramChildren {
+		routes = child.routes(routes)
+	}
+	if n.catchAll != nil {
+		routes = n.catchAll.routes(routes)
+	}
+	return routes
+}
+
+// searchFold is like search, but compares static text case-insensitively and does not
+// collect parameters. It returns the matching route along with buf extended by the path
+// in the casing of the route's pattern.
+func (n *node) searchFold(path string, buf []byte) (*Route, []byte) {
+	if len(path) == 0 {
+		return n.route, buf
+	}
+
+	for _, child := range n.children {
+		if len(path) >= len(child.prefix) && strings.EqualFold(path[:len(child.prefix)], child.prefix) {
+			if r, fixed := child.searchFold(path[len(child.prefix):], append(buf, child.prefix...)); r != nil {
+				return r, fixed
+			}
+		}
+	}
+
+	if len(n.paramChildren) > 0 {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		for _, child := range n.paramChildren {
+			if child.inSegment {
+				for i := end - 1; i > 0; i-- {
+					if r, fixed := child.searchFoldValue(path, i, buf); r != nil {
+						return r, fixed
+					}
+				}
+			}
+			if end > 0 {
+				if r, fixed := child.searchFoldValue(path, end, buf); r != nil {
+					return r, fixed
+				}
+			}
+		}
+	}
+
+	if n.catchAll != nil {
+		if len(n.catchAll.children) > 0 {
+			for i := len(path) - 1; i > 0; i-- {
+				if r, fixed := n.catchAll.searchFoldValue(path, i, buf); r != nil {
+					return r, fixed
+				}
+			}
+		}
+		if n.catchAll.route != nil {
+			return n.catchAll.route, append(buf, path...)
+		}
+	}
+
+	return nil, buf
+}
+
+// searchFoldValue is like searchValue for searchFold.
+func (n *node) searchFoldValue(path string, end int, buf []byte) (*Route, []byte) {
+	value := path[:end]
+	if n.constraint != nil && !n.constraint(value) {
+		return nil, buf
+	}
+	return n.searchFold(path[end:], append(buf, value...))
+}
+
+// clone returns a deep copy of the node and its descendants sharing the routes.
+func (n *node) clone() *node {
+	c := *n
+	c.children = make([]*node, len(n.children))
+	for i, child := range n.children {
+		c.children[i] = child.clone()
+	}
+	c.paramChildren = make([]*node, len(n.paramChildren))
+	for i, child := range n.paramChildren {
+		c.paramChildren[i] = child.clone()
+	}
+	if n.catchAll != nil {
+		c.catchAll = n.catchAll.clone()
+	}
+	return &c
+}
+
+// leaf returns the node where the canonical pattern ends, or nil if the pattern was never
+// inserted. Patterns only differing in parameter names share the same leaf.
+func (n *node) leaf(pattern string) *node {
+	for _, token := range patternTokens(pattern) {
+		switch token.kind {
+		case paramNode:
+			_, constraint := splitParam(token.text)
+			var next *node
+			for _, child := range n.paramChildren {
+				if _, c := splitParam(child.prefix); c == constraint {
+					next = child
+					break
+				}
+			}
+			n = next
+		case catchAllNode:
+			n = n.catchAll
+		default:
+			text := token.text
+			for n != nil && len(text) > 0 {
+				i := strings.IndexByte(n.indices, text[0])
+				if i < 0 || !strings.HasPrefix(text, n.children[i].prefix) {
+					return nil
+				}
+				text = text[len(n.children[i].prefix):]
+				n = n.children[i]
+			}
+		}
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
 type router struct {
-	Roots map[string]*node `json:"roots"`
+	Roots       map[string]*node `json:"roots"`
+	versions    map[string]*router
+	constraints map[string]ParamConstraint
+	strictSlash bool
+}
+
+// clone returns a deep copy of the router sharing the routes and the constraints.
+func (r *router) clone() *router {
+	c := &router{
+		Roots:       make(map[string]*node, len(r.Roots)),
+		constraints: r.constraints,
+		strictSlash: r.strictSlash,
+	}
+	for method, root := range r.Roots {
+		c.Roots[method] = root.clone()
+	}
+	if r.versions != nil {
+		c.versions = make(map[string]*router, len(r.versions))
+		for version, v := range r.versions {
+			c.versions[version] = v.clone()
+		}
+	}
+	return c
+}
+
+// setStrictSlash sets whether the router and its version routers are strict about trailing
+// slashes, and inserts their routes again under their new canonical patterns, in the order
+// of the tree so that constrained parameters keep their order. It returns a
+// *RouteConflictError if two routes only differ in a trailing slash and strict is false.
+func (r *router) setStrictSlash(strict bool) error {
+	if r.strictSlash != strict {
+		r.strictSlash = strict
+		roots := r.Roots
+		r.Roots = make(map[string]*node, len(roots))
+		for method, root := range roots {
+			r.Roots[method] = &node{}
+			for _, rt := range root.routes(nil) {
+				if existing := r.Roots[method].insert(r.canonicalPath(rt.pattern), rt, r.constraints); existing != nil {
+					return &RouteConflictError{
+						Method:          method,
+						Pattern:         rt.pattern,
+						Source:          rt.source,
+						ExistingPattern: existing.pattern,
+						ExistingSource:  existing.source,
+					}
+				}
+			}
+		}
+	}
+	for _, v := range r.versions {
+		if err := v.setStrictSlash(strict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// leaf returns the node where the given pattern ends in the tree of the method, or nil.
+func (r *router) leaf(method string, pattern string) *node {
+	root, ok := r.Roots[method]
+	if !ok {
+		return nil
+	}
+	return root.leaf(r.canonicalPath(pattern))
 }
 
 func newRouter() *router {
 	return &router{
-		Roots: make(map[string]*node),
+		Roots:       make(map[string]*node),
+		constraints: defaultConstraints(),
 	}
 }
 
-func (r *router) addRoute(method string, pattern string, handlers []HandlerFunc) {
-	parts := parsePattern(pattern)
+// addRoute registers the handlers for the given method and pattern and returns the stored route.
+// It returns a *RouteConflictError if the pattern conflicts with an existing route, and panics
+// if the method is not a valid token.
+func (r *router) addRoute(method string, pattern string, handlers []HandlerFunc) (*Route, error) {
+	if !isMethodToken(method) {
+		panic(fmt.Sprintf("invalid HTTP method '%s'", method))
+	}
+	canonical := r.canonicalPath(pattern)
+	rt := &Route{method: method, pattern: pattern, handlers: handlers, source: callerSource()}
+	rt.origin = rt
+	for _, token := range patternTokens(canonical) {
+		if token.kind != staticNode {
+			name, _ := splitParam(token.text)
+			rt.paramKeys = append(rt.paramKeys, name)
+		}
+	}
 
 	if r.Roots[method] == nil {
 		r.Roots[method] = &node{}
 	}
-	r.Roots[method].insert(pattern, parts, 0, handlers)
+	if existing := r.Roots[method].insert(canonical, rt, r.constraints); existing != nil {
+		return nil, &RouteConflictError{
+			Method:          method,
+			Pattern:         pattern,
+			Source:          rt.source,
+			ExistingPattern: existing.pattern,
+			ExistingSource:  existing.source,
+		}
+	}
+	return rt, nil
 }
 
+// findRoute returns the handlers and URL parameters of the route matching the given method and path.
 func (r *router) findRoute(method string, path string) ([]HandlerFunc, map[string]string) {
-	searchParts := parsePattern(path)
-	params := make(map[string]string)
+	var params Params
+	rt := r.getRoute(method, path, &params)
+	if rt == nil {
+		return nil, nil
+	}
+	return rt.handlers, params.toMap()
+}
+
+// getRoute returns the route matching the given method and path, appending its URL parameters
+// to params. It does not allocate when path is canonical and params has enough capacity.
+func (r *router) getRoute(method string, path string, params *Params) *Route {
 	root, ok := r.Roots[method]
+	if !ok {
+		return nil
+	}
+
+	start := len(*params)
+	rt := root.search(r.canonicalPath(path), params)
+	if rt == nil {
+		*params = (*params)[:start]
+		return nil
+	}
 
+	matched := (*params)[start:]
+	for i, key := range rt.paramKeys {
+		matched[i].Key = key
+	}
+	return rt
+}
+
+// findCaseInsensitive returns the route matching the given method and path with static
+// text compared case-insensitively, along with the path in the casing of the route's pattern.
+func (r *router) findCaseInsensitive(method string, path string) (*Route, string) {
+	root, ok := r.Roots[method]
 	if !ok {
-		return nil, nil
+		return nil, ""
+	}
+
+	path = r.canonicalPath(path)
+	rt, fixed := root.searchFold(path, make([]byte, 0, len(path)))
+	if rt == nil {
+		return nil, ""
 	}
+	return rt, string(fixed)
+}
 
-	n := root.search(searchParts, 0)
-	if n != nil {
-		parts := parsePattern(n.Pattern)
-		for index, part := range parts {
-			if part[0] == ':' {
-				params[part[1:]] = searchParts[index]
+// canonicalPath removes empty segments from a request path or route pattern. The trailing
+// slash is removed as well, unless the router is strict about it.
+func (r *router) canonicalPath(path string) string {
+	if !r.strictSlash || len(path) < 2 || path[len(path)-1] != '/' {
+		return canonicalPath(path)
+	}
+	if trimmed := path[:len(path)-1]; isCanonicalPath(trimmed) {
+		return path
+	}
+	if canonical := canonicalPath(path); canonical != "/" {
+		return canonical + "/"
+	}
+	return "/"
+}
+
+// routes appends the routes of every method and API version to routes.
+func (r *router) routes(routes []*Route) []*Route {
+	for _, root := range r.Roots {
+		routes = root.routes(routes)
+	}
+	for _, v := range r.versions {
+		routes = v.routes(routes)
+	}
+	return routes
+}
+
+// matchingRoutes returns the routes of every method matching the given path, sorted by method.
+func (r *router) matchingRoutes(path string) []*Route {
+	var routes []*Route
+	var params Params
+	for method := range r.Roots {
+		if rt := r.getRoute(method, path, &params); rt != nil {
+			routes = append(routes, rt)
+		}
+		params = params[:0]
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].method < routes[j].method
+	})
+	return routes
+}
+
+// patternToken is a static, parameter or catch-all part of a route pattern.
+type patternToken struct {
+	kind nodeKind
+	text string
+}
+
+// patternTokens splits a canonical route pattern into static text, parameters such as
+// `:id<int>` and catch-alls such as `*path`. Wildcards may appear anywhere in a segment,
+// but two wildcards must be separated by static text, otherwise patternTokens panics.
+// Literal colons and stars are escaped by doubling them, as in `/v1/items::batchGet`.
+func patternTokens(pattern string) []patternToken {
+	var tokens []patternToken
+	for len(pattern) > 0 {
+		token := patternToken{kind: wildcardKind(pattern)}
+		end := 0
+		if token.kind == staticNode {
+			token.text, end = staticText(pattern)
+		} else {
+			end = wildcardLength(pattern)
+			if last := len(tokens) - 1; last >= 0 && tokens[last].kind != staticNode {
+				panic(fmt.Sprintf("wildcards '%s' and '%s' must be separated by static text", tokens[last].text, pattern[:end]))
 			}
-			if part[0] == '*' && len(part) > 1 {
-				params[part[1:]] = strings.Join(searchParts[index:], "/")
-				break
+			token.text = pattern[:end]
+		}
+		tokens = append(tokens, token)
+		pattern = pattern[end:]
+	}
+	return tokens
+}
+
+// wildcardKind returns the kind of the wildcard at the start of pattern, or staticNode if
+// it does not start with one.
+func wildcardKind(pattern string) nodeKind {
+	if len(pattern) > 1 && pattern[1] == pattern[0] {
+		return staticNode
+	}
+	switch pattern[0] {
+	case ':':
+		return paramNode
+	case '*':
+		return catchAllNode
+	}
+	return staticNode
+}
+
+// staticText returns the static text at the start of pattern, up to the next wildcard, with
+// the escaped `::` and `**` replaced by a single colon or star, and the length of pattern
+// it spans.
+func staticText(pattern string) (string, int) {
+	var text []byte
+	start := 0
+	for {
+		i := strings.IndexAny(pattern[start:], ":*")
+		if i < 0 {
+			i = len(pattern)
+		} else {
+			i += start
+		}
+		if i+1 >= len(pattern) || pattern[i+1] != pattern[i] {
+			if text == nil {
+				return pattern[:i], i
+			}
+			return string(append(text, pattern[start:i]...)), i
+		}
+		text = append(text, pattern[start:i+1]...)
+		start = i + 2
+	}
+}
+
+// wildcardLength returns the length of the wildcard at the start of pattern: the `:` or `*`
+// marker, the name, see nameLength, and for parameters the optional constraint in angle
+// brackets or parentheses.
+func wildcardLength(pattern string) int {
+	i := 1 + nameLength(pattern[1:])
+	if pattern[0] != ':' || i == len(pattern) {
+		return i
+	}
+
+	switch pattern[i] {
+	case '<':
+		if end := strings.IndexByte(pattern[i:], '>'); end >= 0 {
+			return i + end + 1
+		}
+	case '(':
+		depth := 0
+		for j := i; j < len(pattern); j++ {
+			switch pattern[j] {
+			case '\\':
+				j++
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					return j + 1
+				}
 			}
 		}
-		return n.handlers, params
+	default:
+		return i
 	}
+	panic(fmt.Sprintf("unterminated constraint in '%s'", pattern))
+}
 
-	return nil, nil
+// isMethodToken reports whether method is a valid HTTP method, which is a token as defined
+// by RFC 9110.
+func isMethodToken(method string) bool {
+	if method == "" {
+		return false
+	}
+	for i := 0; i < len(method); i++ {
+		b := method[i]
+		if !isLetter(b) && (b < '0' || b > '9') && !strings.ContainsRune("!#$%&'*+-.^_`|~", rune(b)) {
+			return false
+		}
+	}
+	return true
+}
+
+func isNameByte(b byte) bool {
+	return isLetter(b) || (b >= '0' && b <= '9') || b == '_'
+}
+
+// nameLength returns the length of the parameter name at the start of s, made of letters,
+// digits, underscores and hyphens followed by one of them, so that `:user-id` names the
+// parameter "user-id" while `:from-:to` names two parameters separated by a hyphen.
+func nameLength(s string) int {
+	i := 0
+	for i < len(s) && (isNameByte(s[i]) || s[i] == '-' && i+1 < len(s) && isNameByte(s[i+1])) {
+		i++
+	}
+	return i
+}
+
+// commonPrefixLength returns the length of the longest common prefix of a and b.
+func commonPrefixLength(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
 }
@@ -0,0 +1,655 @@
+package lightning
+
+import (
+	"strings"
+	"testing"
+)
+
+func newBenchmarkRouter() *router {
+	r := newRouter()
+	handler := []HandlerFunc{func(c *Context) {}}
+	for _, pattern := range []string{
+		"/",
+		"/ping",
+		"/users",
+		"/users/new",
+		"/users/:id",
+		"/users/:id/posts",
+		"/users/:id/posts/:postId",
+		"/user_groups",
+		"/static/*filepath",
+	} {
+		r.addRoute(MethodGet, pattern, handler)
+	}
+	return r
+}
+
+func TestNodeInsertCompressesPrefixes(t *testing.T) {
+	root := &node{}
+	root.insert("/users", &Route{pattern: "/users"}, nil)
+	root.insert("/user_groups", &Route{pattern: "/user_groups"}, nil)
+
+	if len(root.children) != 1 {
+		t.Fatalf("Expected 1 child, got %d", len(root.children))
+	}
+	user := root.children[0]
+	if user.prefix != "/user" {
+		t.Errorf("Expected shared prefix '/user', got '%s'", user.prefix)
+	}
+	if len(user.children) != 2 {
+		t.Fatalf("Expected 2 children below '/user', got %d", len(user.children))
+	}
+	if user.children[0].prefix != "s" || user.children[1].prefix != "_groups" {
+		t.Errorf("Unexpected children '%s' and '%s'", user.children[0].prefix, user.children[1].prefix)
+	}
+}
+
+func TestNodeInsertWildcards(t *testing.T) {
+	root := &node{}
+	root.insert("/users/:id/files/*path", &Route{pattern: "/users/:id/files/*path"}, nil)
+
+	users := root.children[0]
+	if users.prefix != "/users/" {
+		t.Fatalf("Expected prefix '/users/', got '%s'", users.prefix)
+	}
+	if len(users.paramChildren) != 1 || users.paramChildren[0].prefix != ":id" || users.paramChildren[0].kind != paramNode {
+		t.Fatal("Expected ':id' param child")
+	}
+	files := users.paramChildren[0].children[0]
+	if files.catchAll == nil || files.catchAll.prefix != "*path" || files.catchAll.kind != catchAllNode {
+		t.Fatal("Expected '*path' catch-all child")
+	}
+	if files.catchAll.route == nil || files.catchAll.route.pattern != "/users/:id/files/*path" {
+		t.Errorf("Expected route on catch-all node, got %v", files.catchAll.route)
+	}
+}
+
+func TestRouterGetRoute(t *testing.T) {
+	r := newBenchmarkRouter()
+
+	tests := []struct {
+		path    string
+		pattern string
+		params  Params
+	}{
+		{"/", "/", nil},
+		{"/ping", "/ping", nil},
+		{"/users", "/users", nil},
+		{"/users/new", "/users/new", nil},
+		{"/users/42", "/users/:id", Params{{Key: "id", Value: "42"}}},
+		{"/users/42/posts", "/users/:id/posts", Params{{Key: "id", Value: "42"}}},
+		{"/users/42/posts/7", "/users/:id/posts/:postId", Params{{Key: "id", Value: "42"}, {Key: "postId", Value: "7"}}},
+		{"/user_groups", "/user_groups", nil},
+		{"/static/css/app.css", "/static/*filepath", Params{{Key: "filepath", Value: "css/app.css"}}},
+		{"/users/", "/users", nil},
+		{"//users//42/", "/users/:id", Params{{Key: "id", Value: "42"}}},
+		{"/user", "", nil},
+		{"/users/42/comments", "", nil},
+		{"/static", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var params Params
+			rt := r.getRoute(MethodGet, tt.path, &params)
+			if tt.pattern == "" {
+				if rt != nil {
+					t.Errorf("Expected no route, got '%s'", rt.pattern)
+				}
+				if len(params) != 0 {
+					t.Errorf("Expected no params, got %v", params)
+				}
+				return
+			}
+			if rt == nil {
+				t.Fatalf("Expected route '%s', got nil", tt.pattern)
+			}
+			if rt.pattern != tt.pattern {
+				t.Errorf("Expected route '%s', got '%s'", tt.pattern, rt.pattern)
+			}
+			if len(params) != len(tt.params) {
+				t.Fatalf("Expected params %v, got %v", tt.params, params)
+			}
+			for i := range params {
+				if params[i] != tt.params[i] {
+					t.Errorf("Expected params %v, got %v", tt.params, params)
+				}
+			}
+		})
+	}
+}
+
+func TestRouterBacktracksToParam(t *testing.T) {
+	r := newRouter()
+	r.addRoute(MethodGet, "/users/new/edit", []HandlerFunc{func(c *Context) {}})
+	r.addRoute(MethodGet, "/users/:id", []HandlerFunc{func(c *Context) {}})
+
+	var params Params
+	rt := r.getRoute(MethodGet, "/users/new", &params)
+	if rt == nil || rt.pattern != "/users/:id" {
+		t.Fatal("Expected '/users/new' to fall back to '/users/:id'")
+	}
+	if params.Get("id") != "new" {
+		t.Errorf("Expected id=new, got '%s'", params.Get("id"))
+	}
+}
+
+func TestRouterPrecedence(t *testing.T) {
+	patterns := []string{
+		"/users/new",
+		"/users/:id",
+		"/users/:id/posts",
+		"/users/*rest",
+	}
+
+	tests := []struct {
+		path    string
+		pattern string
+	}{
+		{"/users/new", "/users/new"},
+		{"/users/newer", "/users/:id"},
+		{"/users/42", "/users/:id"},
+		{"/users/new/posts", "/users/:id/posts"},
+		{"/users/42/posts", "/users/:id/posts"},
+		{"/users/42/comments", "/users/*rest"},
+		{"/users/42/posts/7", "/users/*rest"},
+	}
+
+	// Every registration order must produce the same matches.
+	for _, order := range permutations(patterns) {
+		r := newRouter()
+		for _, pattern := range order {
+			r.addRoute(MethodGet, pattern, []HandlerFunc{func(c *Context) {}})
+		}
+
+		for _, tt := range tests {
+			var params Params
+			rt := r.getRoute(MethodGet, tt.path, &params)
+			if rt == nil {
+				t.Fatalf("%v: expected '%s' to match '%s', got nil", order, tt.path, tt.pattern)
+			}
+			if rt.pattern != tt.pattern {
+				t.Errorf("%v: expected '%s' to match '%s', got '%s'", order, tt.path, tt.pattern, rt.pattern)
+			}
+		}
+	}
+}
+
+func TestRouterBacktracksToCatchAll(t *testing.T) {
+	r := newRouter()
+	r.addRoute(MethodGet, "/files/*path", []HandlerFunc{func(c *Context) {}})
+	r.addRoute(MethodGet, "/files/:dir/:name/meta", []HandlerFunc{func(c *Context) {}})
+	r.addRoute(MethodGet, "/files/docs/index", []HandlerFunc{func(c *Context) {}})
+
+	var params Params
+	rt := r.getRoute(MethodGet, "/files/docs/readme", &params)
+	if rt == nil || rt.pattern != "/files/*path" {
+		t.Fatal("Expected '/files/docs/readme' to fall back to '/files/*path'")
+	}
+	if len(params) != 1 || params.Get("path") != "docs/readme" {
+		t.Errorf("Expected only path=docs/readme, got %v", params)
+	}
+
+	params = params[:0]
+	rt = r.getRoute(MethodGet, "/files/docs/readme/meta", &params)
+	if rt == nil || rt.pattern != "/files/:dir/:name/meta" {
+		t.Fatal("Expected '/files/docs/readme/meta' to match '/files/:dir/:name/meta'")
+	}
+	if params.Get("dir") != "docs" || params.Get("name") != "readme" {
+		t.Errorf("Expected dir=docs and name=readme, got %v", params)
+	}
+}
+
+// permutations returns every ordering of the given strings.
+func permutations(values []string) [][]string {
+	if len(values) <= 1 {
+		return [][]string{append([]string{}, values...)}
+	}
+	var result [][]string
+	for i := range values {
+		rest := make([]string, 0, len(values)-1)
+		rest = append(rest, values[:i]...)
+		rest = append(rest, values[i+1:]...)
+		for _, p := range permutations(rest) {
+			result = append(result, append([]string{values[i]}, p...))
+		}
+	}
+	return result
+}
+
+func TestRouterConstraints(t *testing.T) {
+	r := newRouter()
+	r.addRoute(MethodGet, "/users/:id<int>", []HandlerFunc{func(c *Context) {}})
+	r.addRoute(MethodGet, "/users/:uuid<uuid>", []HandlerFunc{func(c *Context) {}})
+	r.addRoute(MethodGet, "/users/:slug([a-z0-9-]+)", []HandlerFunc{func(c *Context) {}})
+	r.addRoute(MethodGet, "/posts/:id<int>", []HandlerFunc{func(c *Context) {}})
+
+	tests := []struct {
+		path    string
+		pattern string
+		key     string
+		value   string
+	}{
+		{"/users/42", "/users/:id<int>", "id", "42"},
+		{"/users/123e4567-e89b-12d3-a456-426614174000", "/users/:uuid<uuid>", "uuid", "123e4567-e89b-12d3-a456-426614174000"},
+		{"/users/john-doe", "/users/:slug([a-z0-9-]+)", "slug", "john-doe"},
+		{"/users/John", "", "", ""},
+		{"/posts/abc", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		var params Params
+		rt := r.getRoute(MethodGet, tt.path, &params)
+		if tt.pattern == "" {
+			if rt != nil {
+				t.Errorf("Expected '%s' not to match, got '%s'", tt.path, rt.pattern)
+			}
+			continue
+		}
+		if rt == nil || rt.pattern != tt.pattern {
+			t.Errorf("Expected '%s' to match '%s', got %v", tt.path, tt.pattern, rt)
+			continue
+		}
+		if params.Get(tt.key) != tt.value {
+			t.Errorf("Expected %s=%s, got %v", tt.key, tt.value, params)
+		}
+	}
+}
+
+func TestRouterConstraintFallsThrough(t *testing.T) {
+	r := newRouter()
+	r.addRoute(MethodGet, "/items/:name", []HandlerFunc{func(c *Context) {}})
+	r.addRoute(MethodGet, "/items/:id<int>", []HandlerFunc{func(c *Context) {}})
+
+	var params Params
+	if rt := r.getRoute(MethodGet, "/items/7", &params); rt == nil || rt.pattern != "/items/:id<int>" {
+		t.Errorf("Expected constrained route to be tried first, got %v", rt)
+	}
+	params = params[:0]
+	if rt := r.getRoute(MethodGet, "/items/seven", &params); rt == nil || rt.pattern != "/items/:name" {
+		t.Errorf("Expected unconstrained route as fallback, got %v", rt)
+	}
+	if params.Get("name") != "seven" {
+		t.Errorf("Expected name=seven, got %v", params)
+	}
+}
+
+func TestRouterCustomConstraint(t *testing.T) {
+	r := newRouter()
+	r.constraints["even"] = func(value string) bool {
+		return isUint(value) && (value[len(value)-1]-'0')%2 == 0
+	}
+	r.addRoute(MethodGet, "/numbers/:n<even>", []HandlerFunc{func(c *Context) {}})
+
+	var params Params
+	if r.getRoute(MethodGet, "/numbers/4", &params) == nil {
+		t.Error("Expected '/numbers/4' to match")
+	}
+	if r.getRoute(MethodGet, "/numbers/3", &params) != nil {
+		t.Error("Expected '/numbers/3' not to match")
+	}
+}
+
+func TestRouterConstraintZeroAllocs(t *testing.T) {
+	r := newRouter()
+	r.addRoute(MethodGet, "/users/:id<int>", []HandlerFunc{func(c *Context) {}})
+	params := make(Params, 0, 1)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		params = params[:0]
+		r.getRoute(MethodGet, "/users/42", &params)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected zero allocations, got %v", allocs)
+	}
+}
+
+func TestRouterConflicts(t *testing.T) {
+	tests := []struct {
+		first  string
+		second string
+		kind   string
+	}{
+		{"/users/:id", "/users/:id", "duplicate"},
+		{"/users/:id", "/users/:name", "ambiguous"},
+		{"/users/:id<int>/posts", "/users/:n<int>/posts", "ambiguous"},
+		{"/files/*path", "/files/*rest", "ambiguous"},
+		{"/about/", "/about", "ambiguous"},
+		{"/files/:name.:ext", "/files/:base.:format", "ambiguous"},
+		{"/repos/*path/raw", "/repos/*rest/raw", "ambiguous"},
+	}
+
+	for _, tt := range tests {
+		r := newRouter()
+		if _, err := r.addRoute(MethodGet, tt.first, nil); err != nil {
+			t.Fatalf("Unexpected error for '%s': %v", tt.first, err)
+		}
+		_, err := r.addRoute(MethodGet, tt.second, nil)
+		conflict, ok := err.(*RouteConflictError)
+		if !ok {
+			t.Errorf("Expected RouteConflictError for '%s' after '%s', got %v", tt.second, tt.first, err)
+			continue
+		}
+		if conflict.Pattern != tt.second || conflict.ExistingPattern != tt.first {
+			t.Errorf("Unexpected conflict patterns: %+v", conflict)
+		}
+		if !strings.HasPrefix(err.Error(), tt.kind) {
+			t.Errorf("Expected %s conflict, got '%s'", tt.kind, err)
+		}
+		if !strings.Contains(conflict.Source, "router_test.go:") || !strings.Contains(conflict.ExistingSource, "router_test.go:") {
+			t.Errorf("Expected registration call sites, got '%s' and '%s'", conflict.Source, conflict.ExistingSource)
+		}
+	}
+}
+
+func TestRouterNoConflicts(t *testing.T) {
+	r := newRouter()
+	for _, pattern := range []string{
+		"/users/:id",
+		"/users/:id<int>",
+		"/users/:id([a-z]+)",
+		"/users/*rest",
+		"/users/new",
+		"/users/:id/posts",
+	} {
+		if _, err := r.addRoute(MethodGet, pattern, nil); err != nil {
+			t.Errorf("Unexpected error for '%s': %v", pattern, err)
+		}
+	}
+	if _, err := r.addRoute(MethodPost, "/users/:id", nil); err != nil {
+		t.Errorf("Unexpected error for other method: %v", err)
+	}
+}
+
+func TestRouterUnnamedCatchAll(t *testing.T) {
+	r := newRouter()
+	r.addRoute(MethodGet, "/api/*", []HandlerFunc{func(c *Context) {}})
+
+	_, params := r.findRoute(MethodGet, "/api/users/1")
+	if len(params) != 0 {
+		t.Errorf("Expected no named params, got %v", params)
+	}
+}
+
+func TestRouterMixedSegments(t *testing.T) {
+	r := newRouter()
+	handler := []HandlerFunc{func(c *Context) {}}
+	for _, pattern := range []string{
+		"/files/:name.:ext",
+		"/files/:name",
+		"/v:version/users",
+		"/repos/*path/raw",
+		"/repos/*path",
+		"/flights/:from-:to",
+		"/users/:user-id/posts/:post-id<int>",
+		"/tags/:tag-",
+		"/items/:id<int>.json",
+		"/items/:slug",
+		"/v1/items::batchGet",
+		"/v1/items::batchCreate",
+		"/files/report**.pdf",
+	} {
+		r.addRoute(MethodGet, pattern, handler)
+	}
+
+	tests := []struct {
+		path    string
+		pattern string
+		params  Params
+	}{
+		{"/files/archive.tar.gz", "/files/:name.:ext", Params{{Key: "name", Value: "archive.tar"}, {Key: "ext", Value: "gz"}}},
+		{"/files/report.pdf", "/files/:name.:ext", Params{{Key: "name", Value: "report"}, {Key: "ext", Value: "pdf"}}},
+		{"/files/README", "/files/:name", Params{{Key: "name", Value: "README"}}},
+		{"/files/.env", "/files/:name", Params{{Key: "name", Value: ".env"}}},
+		{"/files/notes.", "/files/:name", Params{{Key: "name", Value: "notes."}}},
+		{"/v2/users", "/v:version/users", Params{{Key: "version", Value: "2"}}},
+		{"/v/users", "", nil},
+		{"/repos/go-labx/lightning/raw", "/repos/*path/raw", Params{{Key: "path", Value: "go-labx/lightning"}}},
+		{"/repos/a/raw/b/raw", "/repos/*path/raw", Params{{Key: "path", Value: "a/raw/b"}}},
+		{"/repos/go-labx/lightning", "/repos/*path", Params{{Key: "path", Value: "go-labx/lightning"}}},
+		{"/flights/AMS-SFO", "/flights/:from-:to", Params{{Key: "from", Value: "AMS"}, {Key: "to", Value: "SFO"}}},
+		{"/users/7/posts/12", "/users/:user-id/posts/:post-id<int>", Params{{Key: "user-id", Value: "7"}, {Key: "post-id", Value: "12"}}},
+		{"/tags/go-", "/tags/:tag-", Params{{Key: "tag", Value: "go"}}},
+		{"/items/42.json", "/items/:id<int>.json", Params{{Key: "id", Value: "42"}}},
+		{"/items/abc.json", "/items/:slug", Params{{Key: "slug", Value: "abc.json"}}},
+		{"/v1/items:batchGet", "/v1/items::batchGet", nil},
+		{"/v1/items:batchCreate", "/v1/items::batchCreate", nil},
+		{"/v1/itemsXYZ", "", nil},
+		{"/files/report*.pdf", "/files/report**.pdf", nil},
+		{"/files/report2024.pdf", "/files/:name.:ext", Params{{Key: "name", Value: "report2024"}, {Key: "ext", Value: "pdf"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var params Params
+			rt := r.getRoute(MethodGet, tt.path, &params)
+			if tt.pattern == "" {
+				if rt != nil {
+					t.Errorf("Expected no route, got '%s'", rt.pattern)
+				}
+				return
+			}
+			if rt == nil {
+				t.Fatalf("Expected route '%s', got nil", tt.pattern)
+			}
+			if rt.pattern != tt.pattern {
+				t.Errorf("Expected route '%s', got '%s'", tt.pattern, rt.pattern)
+			}
+			if len(params) != len(tt.params) {
+				t.Fatalf("Expected params %v, got %v", tt.params, params)
+			}
+			for i := range params {
+				if params[i] != tt.params[i] {
+					t.Errorf("Expected params %v, got %v", tt.params, params)
+				}
+			}
+		})
+	}
+
+	params := make(Params, 0, 4)
+	for _, path := range []string{"/files/archive.tar.gz", "/repos/go-labx/lightning/raw"} {
+		allocs := testing.AllocsPerRun(100, func() {
+			params = params[:0]
+			r.getRoute(MethodGet, path, &params)
+		})
+		if allocs != 0 {
+			t.Errorf("Expected zero allocations for '%s', got %v", path, allocs)
+		}
+	}
+}
+
+func TestRouterAdjacentWildcardsPanic(t *testing.T) {
+	for _, pattern := range []string{"/files/:name:ext", "/files/:name*rest", "/files/*path:name"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for '%s'", pattern)
+				}
+			}()
+			newRouter().addRoute(MethodGet, pattern, nil)
+		}()
+	}
+}
+
+func TestPatternTokens(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []patternToken
+	}{
+		{"/users/:id", []patternToken{{staticNode, "/users/"}, {paramNode, ":id"}}},
+		{"/files/:name.:ext", []patternToken{{staticNode, "/files/"}, {paramNode, ":name"}, {staticNode, "."}, {paramNode, ":ext"}}},
+		{"/v:version<int>/users", []patternToken{{staticNode, "/v"}, {paramNode, ":version<int>"}, {staticNode, "/users"}}},
+		{"/:slug([a-z]+(-[a-z]+)*).html", []patternToken{{staticNode, "/"}, {paramNode, ":slug([a-z]+(-[a-z]+)*)"}, {staticNode, ".html"}}},
+		{"/repos/*path/raw", []patternToken{{staticNode, "/repos/"}, {catchAllNode, "*path"}, {staticNode, "/raw"}}},
+		{"/v1/items::batchGet", []patternToken{{staticNode, "/v1/items:batchGet"}}},
+		{"/files/report**.pdf", []patternToken{{staticNode, "/files/report*.pdf"}}},
+		{"/a::b/:id::c", []patternToken{{staticNode, "/a:b/"}, {paramNode, ":id"}, {staticNode, ":c"}}},
+	}
+
+	for _, tt := range tests {
+		got := patternTokens(tt.pattern)
+		if len(got) != len(tt.want) {
+			t.Errorf("patternTokens(%q) = %v, want %v", tt.pattern, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("patternTokens(%q) = %v, want %v", tt.pattern, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRouterStrictSlash(t *testing.T) {
+	r := newRouter()
+	r.strictSlash = true
+	handler := []HandlerFunc{func(c *Context) {}}
+	for _, pattern := range []string{"/about", "/about/", "/files/*path"} {
+		if _, err := r.addRoute(MethodGet, pattern, handler); err != nil {
+			t.Fatalf("Unexpected error for '%s': %v", pattern, err)
+		}
+	}
+
+	tests := []struct {
+		path    string
+		pattern string
+	}{
+		{"/about", "/about"},
+		{"/about/", "/about/"},
+		{"//about//", "/about/"},
+		{"/files/docs/", "/files/*path"},
+	}
+	for _, tt := range tests {
+		var params Params
+		rt := r.getRoute(MethodGet, tt.path, &params)
+		if rt == nil || rt.pattern != tt.pattern {
+			t.Errorf("Expected route '%s' for '%s', got %v", tt.pattern, tt.path, rt)
+		}
+	}
+
+	if _, params := r.findRoute(MethodGet, "/files/docs/"); params["path"] != "docs/" {
+		t.Errorf("Expected path 'docs/', got '%s'", params["path"])
+	}
+}
+
+func TestRouterFindCaseInsensitive(t *testing.T) {
+	r := newRouter()
+	handler := []HandlerFunc{func(c *Context) {}}
+	for _, pattern := range []string{"/Users/:id<int>", "/users/new", "/files/:name.JSON", "/static/*path/Raw"} {
+		r.addRoute(MethodGet, pattern, handler)
+	}
+
+	tests := []struct {
+		path  string
+		fixed string
+	}{
+		{"/USERS/42", "/Users/42"},
+		{"/USERS/NEW", "/users/new"},
+		{"/files/Report.json", "/files/Report.JSON"},
+		{"/STATIC/A/b/raw", "/static/A/b/Raw"},
+		{"/users/abc", ""},
+		{"/missing", ""},
+	}
+	for _, tt := range tests {
+		rt, fixed := r.findCaseInsensitive(MethodGet, tt.path)
+		if tt.fixed == "" {
+			if rt != nil {
+				t.Errorf("Expected no route for '%s', got '%s'", tt.path, rt.pattern)
+			}
+			continue
+		}
+		if rt == nil || fixed != tt.fixed {
+			t.Errorf("Expected '%s' for '%s', got '%s'", tt.fixed, tt.path, fixed)
+		}
+	}
+}
+
+func TestRouteOptions(t *testing.T) {
+	app := NewApp()
+	r := app.AddRoute(MethodGet, "/users/:id", []HandlerFunc{func(c *Context) {}},
+		WithName("user"), WithMeta("scopes", []string{"users:read"}))
+	r.With(WithMeta("rateLimit", "burst"))
+
+	if r.Method() != MethodGet || r.Pattern() != "/users/:id" || r.Name() != "user" {
+		t.Errorf("Unexpected route %s %s named '%s'", r.Method(), r.Pattern(), r.Name())
+	}
+	if scopes, ok := r.Meta("scopes"); !ok || scopes.([]string)[0] != "users:read" {
+		t.Errorf("Expected scopes metadata, got %v", scopes)
+	}
+	if class, _ := r.Meta("rateLimit"); class != "burst" {
+		t.Errorf("Expected rate-limit class 'burst', got %v", class)
+	}
+	if _, ok := r.Meta("missing"); ok {
+		t.Error("Expected missing metadata to be reported")
+	}
+	if url, _ := app.URL("user", "id", 1); url != "/users/1" {
+		t.Errorf("Expected named route URL '/users/1', got '%s'", url)
+	}
+
+	g := app.Group("/api")
+	gr := g.AddRoute(MethodPost, "/items", []HandlerFunc{func(c *Context) {}}, WithMeta("description", "Create an item"))
+	if description, _ := gr.Meta("description"); description != "Create an item" {
+		t.Errorf("Expected description metadata on group route, got %v", description)
+	}
+}
+
+func TestParamsGet(t *testing.T) {
+	params := Params{{Key: "id", Value: "1"}, {Key: "name", Value: "foo"}}
+	if params.Get("name") != "foo" {
+		t.Errorf("Expected 'foo', got '%s'", params.Get("name"))
+	}
+	if params.Get("missing") != "" {
+		t.Errorf("Expected empty value, got '%s'", params.Get("missing"))
+	}
+}
+
+func TestRouterGetRouteZeroAllocs(t *testing.T) {
+	r := newBenchmarkRouter()
+	params := make(Params, 0, 4)
+
+	for _, path := range []string{"/users/new", "/users/42/posts/7", "/static/css/app.css"} {
+		allocs := testing.AllocsPerRun(100, func() {
+			params = params[:0]
+			if r.getRoute(MethodGet, path, &params) == nil {
+				t.Fatalf("Expected route for '%s'", path)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("Expected zero allocations for '%s', got %v", path, allocs)
+		}
+	}
+}
+
+func BenchmarkRouterStatic(b *testing.B) {
+	r := newBenchmarkRouter()
+	params := make(Params, 0, 4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		params = params[:0]
+		r.getRoute(MethodGet, "/users/new", &params)
+	}
+}
+
+func BenchmarkRouterParam(b *testing.B) {
+	r := newBenchmarkRouter()
+	params := make(Params, 0, 4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		params = params[:0]
+		r.getRoute(MethodGet, "/users/42/posts/7", &params)
+	}
+}
+
+func BenchmarkRouterCatchAll(b *testing.B) {
+	r := newBenchmarkRouter()
+	params := make(Params, 0, 4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		params = params[:0]
+		r.getRoute(MethodGet, "/static/css/app.css", &params)
+	}
+}
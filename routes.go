@@ -0,0 +1,100 @@
+package lightning
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// RouteInfo describes a registered route. The Method of routes added with Any is MethodAny.
+type RouteInfo struct {
+	Method      string         `json:"method"`
+	Pattern     string         `json:"pattern"`
+	Host        string         `json:"host,omitempty"`
+	Version     string         `json:"version,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	Handlers    []string       `json:"handlers"`
+	Middlewares int            `json:"middlewares"`
+	Group       string         `json:"group,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+	Request     string         `json:"request,omitempty"`
+	Response    string         `json:"response,omitempty"`
+}
+
+// Routes returns the registered routes, sorted by host, pattern, API version and method.
+// Handlers holds the names of the route's own handler functions, while Middlewares counts
+// the middlewares of the Application and the route's Group that run before them. Request and
+// Response name the types recorded with WithTypes.
+func (app *Application) Routes() []RouteInfo {
+	routes := app.routes().routes()
+
+	infos := make([]RouteInfo, 0, len(routes))
+	for _, r := range routes {
+		info := RouteInfo{
+			Method:      r.method,
+			Pattern:     r.pattern,
+			Name:        r.name,
+			Handlers:    make([]string, 0, len(r.handlers)),
+			Middlewares: app.middlewareCount(r.group),
+			Metadata:    r.meta,
+		}
+		for _, handler := range r.handlers {
+			info.Handlers = append(info.Handlers, handlerName(handler))
+		}
+		info.Host = r.host
+		info.Version = r.version
+		if r.request != nil {
+			info.Request, info.Response = r.request.String(), r.response.String()
+		}
+		if r.group != nil {
+			info.Group = r.group.getFullPrefix()
+		}
+		infos = append(infos, info)
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Host != infos[j].Host {
+			return infos[i].Host < infos[j].Host
+		}
+		if infos[i].Pattern != infos[j].Pattern {
+			return infos[i].Pattern < infos[j].Pattern
+		}
+		if infos[i].Version != infos[j].Version {
+			return infos[i].Version < infos[j].Version
+		}
+		return infos[i].Method < infos[j].Method
+	})
+	return infos
+}
+
+// logRoutes prints a table of the registered routes with the Logger.
+func (app *Application) logRoutes() {
+	for _, line := range routesTable(app.Routes()) {
+		app.Logger.Debug(" %s", line)
+	}
+}
+
+// routesTable formats the routes as the lines of an aligned table with a header.
+func routesTable(routes []RouteInfo) []string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tHOST\tPATTERN\tVERSION\tNAME\tHANDLERS\tMIDDLEWARES")
+	for _, r := range routes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n", r.Method, r.Host, r.Pattern, r.Version, r.Name, strings.Join(r.Handlers, ", "), r.Middlewares)
+	}
+	w.Flush()
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+// handlerName returns the name of the handler function, as reported by the runtime.
+func handlerName(handler HandlerFunc) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	return fn.Name()
+}
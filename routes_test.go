@@ -0,0 +1,86 @@
+package lightning
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func listUsers(c *Context) {}
+
+func TestApplicationRoutes(t *testing.T) {
+	app := NewApp()
+	app.Use(func(c *Context) { c.Next() })
+	app.Get("/users", listUsers).With(WithName("users"), WithMeta("scope", "users:read"))
+	api := app.Group("/api")
+	api.Use(func(c *Context) { c.Next() })
+	api.Post("/users/:id", func(c *Context) {}, listUsers)
+	app.Host("admin.example.com").Get("/", listUsers)
+
+	routes := app.Routes()
+	if len(routes) != 3 {
+		t.Fatalf("Expected 3 routes, got %d: %+v", len(routes), routes)
+	}
+
+	users := routes[1]
+	if users.Method != MethodGet || users.Pattern != "/users" || users.Name != "users" {
+		t.Errorf("Unexpected route info %+v", users)
+	}
+	if len(users.Handlers) != 1 || users.Handlers[0] != "github.com/go-labx/lightning.listUsers" {
+		t.Errorf("Expected handler name of listUsers, got %v", users.Handlers)
+	}
+	if users.Middlewares != 1 || users.Group != "" || users.Host != "" {
+		t.Errorf("Unexpected route info %+v", users)
+	}
+	if users.Metadata["scope"] != "users:read" {
+		t.Errorf("Expected metadata, got %v", users.Metadata)
+	}
+
+	post := routes[0]
+	if post.Method != MethodPost || post.Pattern != "/api/users/:id" || post.Group != "/api" {
+		t.Errorf("Unexpected route info %+v", post)
+	}
+	if len(post.Handlers) != 2 || post.Middlewares != 2 {
+		t.Errorf("Expected 2 handlers and 2 middlewares, got %+v", post)
+	}
+
+	admin := routes[2]
+	if admin.Host != "admin.example.com" || admin.Pattern != "/" {
+		t.Errorf("Expected host route last, got %+v", admin)
+	}
+}
+
+func TestRoutesTable(t *testing.T) {
+	lines := routesTable([]RouteInfo{
+		{Method: MethodGet, Pattern: "/users", Name: "users", Handlers: []string{"main.listUsers"}, Middlewares: 2},
+		{Method: MethodDelete, Pattern: "/users/:id", Handlers: []string{"main.auth", "main.deleteUser"}},
+	})
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %q", lines)
+	}
+	if !strings.HasPrefix(lines[0], "METHOD") {
+		t.Errorf("Expected header, got %q", lines[0])
+	}
+	if !strings.Contains(lines[2], "main.auth, main.deleteUser") {
+		t.Errorf("Expected handler names, got %q", lines[2])
+	}
+	if strings.Index(lines[1], "/users") != strings.Index(lines[2], "/users/:id") {
+		t.Errorf("Expected aligned columns, got %q", lines)
+	}
+}
+
+func TestDebugEndpointServesRoutes(t *testing.T) {
+	app := NewApp(&Config{EnableDebug: true})
+	app.Get("/users", listUsers)
+
+	ctx := createFasthttpRequest(MethodGet, "/__debug__/router_map")
+	app.serveRequest(ctx)
+
+	var routes []RouteInfo
+	if err := json.Unmarshal(ctx.Response.Body(), &routes); err != nil {
+		t.Fatalf("Expected a JSON list of routes, got %q: %v", ctx.Response.Body(), err)
+	}
+	if len(routes) != 2 || routes[0].Pattern != "/__debug__/router_map" || routes[1].Pattern != "/users" {
+		t.Errorf("Unexpected routes %+v", routes)
+	}
+}
@@ -0,0 +1,257 @@
+package lightning
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// routingTable holds the routes of an Application. Once the Application serves requests,
+// the table in use is never modified: changes are made to a copy, which then replaces it
+// atomically, so that requests in flight keep matching against a consistent table.
+type routingTable struct {
+	router      *router
+	hosts       []*hostRouter
+	namedRoutes map[string]*Route
+}
+
+func newRoutingTable() *routingTable {
+	return &routingTable{
+		router:      newRouter(),
+		namedRoutes: make(map[string]*Route),
+	}
+}
+
+// clone returns a deep copy of the table sharing the routes.
+func (t *routingTable) clone() *routingTable {
+	c := &routingTable{
+		router:      t.router.clone(),
+		hosts:       make([]*hostRouter, len(t.hosts)),
+		namedRoutes: maps.Clone(t.namedRoutes),
+	}
+	for i, h := range t.hosts {
+		c.hosts[i] = &hostRouter{pattern: h.pattern, labels: h.labels, router: h.router.clone()}
+	}
+	return c
+}
+
+// routes returns the routes of the default router and of the host routers.
+func (t *routingTable) routes() []*Route {
+	routes := t.router.routes(nil)
+	for _, h := range t.hosts {
+		routes = h.router.routes(routes)
+	}
+	return routes
+}
+
+// host returns the host router for the given pattern, or nil if there is none.
+func (t *routingTable) host(pattern string) *hostRouter {
+	for _, h := range t.hosts {
+		if h.pattern == pattern {
+			return h
+		}
+	}
+	return nil
+}
+
+// addHost adds a host router for the given pattern unless there already is one.
+// Static host patterns are kept before patterns with parameters.
+func (t *routingTable) addHost(pattern string) {
+	if t.host(pattern) != nil {
+		return
+	}
+
+	h := newHostRouter(pattern, t.router)
+	i := len(t.hosts)
+	if h.isStatic() {
+		for i = 0; i < len(t.hosts) && t.hosts[i].isStatic(); i++ {
+		}
+	}
+	t.hosts = append(t.hosts, nil)
+	copy(t.hosts[i+1:], t.hosts[i:])
+	t.hosts[i] = h
+}
+
+// setStrictSlash sets whether the routers are strict about trailing slashes, see
+// router.setStrictSlash.
+func (t *routingTable) setStrictSlash(strict bool) error {
+	if err := t.router.setStrictSlash(strict); err != nil {
+		return err
+	}
+	for _, h := range t.hosts {
+		if err := h.router.setStrictSlash(strict); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// routerOf returns the router holding the routes of the given host pattern, which is empty
+// for the default router.
+func (t *routingTable) routerOf(host string) *router {
+	if host == "" {
+		return t.router
+	}
+	return t.host(host).router
+}
+
+// routerFor returns the router for the host of the request, appending the values of the
+// host parameters to the Context's params, or the default router if no host pattern matches.
+func (t *routingTable) routerFor(c *Context) *router {
+	if len(t.hosts) == 0 {
+		return t.router
+	}
+
+	host := string(c.ctx.Host())
+	if i := strings.LastIndexByte(host, ':'); i > strings.LastIndexByte(host, ']') {
+		host = host[:i]
+	}
+	for _, h := range t.hosts {
+		if h.match(host, &c.params) {
+			return h.router
+		}
+	}
+	return t.router
+}
+
+// setName registers the route under the given name, releasing its previous name.
+// It panics if the name is already used by another route.
+func (t *routingTable) setName(r *Route, name string) {
+	if err := t.checkName(r, name); err != nil {
+		panic(err.Error())
+	}
+	if r.name != "" && t.namedRoutes[r.name] == r {
+		delete(t.namedRoutes, r.name)
+	}
+	r.name = name
+	t.namedRoutes[name] = r
+}
+
+// checkName returns an error if the name is already used by another route than r.
+func (t *routingTable) checkName(r *Route, name string) error {
+	if other, ok := t.namedRoutes[name]; ok && other != r {
+		return fmt.Errorf("route name '%s' is already used by %s %s", name, other.method, other.pattern)
+	}
+	return nil
+}
+
+// optionsName returns the name the options give to a route, or an empty string.
+func optionsName(options []RouteOption) string {
+	var r Route
+	for _, option := range options {
+		option(&r)
+	}
+	return r.name
+}
+
+// applyOptions applies the options to the route and registers the name they set.
+func (t *routingTable) applyOptions(r *Route, options []RouteOption) {
+	name := r.name
+	for _, option := range options {
+		option(r)
+	}
+	if r.name != name {
+		newName := r.name
+		r.name = name
+		t.setName(r, newName)
+	}
+}
+
+// liveRoute returns the leaf storing the route r or a copy of it, or nil if it was removed.
+func (t *routingTable) liveRoute(r *Route) *node {
+	h := t.router
+	if r.host != "" {
+		h = t.host(r.host).router
+	}
+	router := h.versioned(r.version)
+	if router == nil {
+		return nil
+	}
+	n := router.leaf(r.method, r.pattern)
+	if n == nil || n.route == nil || n.route.origin != r.origin {
+		return nil
+	}
+	return n
+}
+
+// removeRoute removes the route registered for the method and pattern in the router of the
+// given host pattern and API version, and releases its name. It returns the removed route, or nil.
+func (t *routingTable) removeRoute(host string, version string, method string, pattern string) *Route {
+	router := t.router
+	if host != "" {
+		h := t.host(host)
+		if h == nil {
+			return nil
+		}
+		router = h.router
+	}
+	if router = router.versioned(version); router == nil {
+		return nil
+	}
+
+	n := router.leaf(method, pattern)
+	if n == nil || n.route == nil || router.canonicalPath(n.route.pattern) != router.canonicalPath(pattern) {
+		return nil
+	}
+	r := n.route
+	n.route = nil
+	if r.name != "" && t.namedRoutes[r.name] == r {
+		delete(t.namedRoutes, r.name)
+	}
+	return r
+}
+
+// routes returns the routing table to use for requests.
+func (app *Application) routes() *routingTable {
+	return app.table.Load()
+}
+
+// updateRoutes calls fn to change the routing table. Once the Application serves requests,
+// fn is given a copy of the table, which replaces it unless fn fails or panics.
+// Changes are serialized, so fn may read the table it is given without further locking.
+func (app *Application) updateRoutes(fn func(t *routingTable) error) error {
+	app.tableMu.Lock()
+	defer app.tableMu.Unlock()
+
+	t := app.table.Load()
+	if !app.serving.Load() {
+		return fn(t)
+	}
+
+	t = t.clone()
+	if err := fn(t); err != nil {
+		return err
+	}
+	app.table.Store(t)
+	return nil
+}
+
+// updateRoute calls fn to change the route in use, which is r or the copy that replaced it,
+// and returns it. Once the Application serves requests, the route in use is not modified:
+// fn is given a copy, which replaces it in the routing table. updateRoute panics if the route
+// was removed, leaving the routing table unchanged.
+func (app *Application) updateRoute(r *Route, fn func(t *routingTable, r *Route)) *Route {
+	var updated *Route
+	err := app.updateRoutes(func(t *routingTable) error {
+		n := t.liveRoute(r)
+		if n == nil {
+			return fmt.Errorf("route %s %s was removed", r.method, r.pattern)
+		}
+		updated = n.route
+		if app.serving.Load() {
+			c := *n.route
+			c.meta = maps.Clone(n.route.meta)
+			updated = &c
+			if name := c.name; name != "" && t.namedRoutes[name] == n.route {
+				t.namedRoutes[name] = updated
+			}
+			n.route = updated
+		}
+		fn(t, updated)
+		return nil
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	return updated
+}
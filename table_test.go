@@ -0,0 +1,208 @@
+package lightning
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestRemoveRoute(t *testing.T) {
+	app := NewApp()
+	app.Get("/users/:id", func(c *Context) {
+		c.Text(StatusOK, "user")
+	}).With(WithName("user"))
+	app.Post("/users/:id", func(c *Context) {})
+
+	if app.RemoveRoute(MethodGet, "/users/:name") {
+		t.Error("Expected no route to be removed for a different parameter name")
+	}
+	if !app.RemoveRoute(MethodGet, "/users/:id") {
+		t.Fatal("Expected the route to be removed")
+	}
+	if app.RemoveRoute(MethodGet, "/users/:id") {
+		t.Error("Expected the route to be removed only once")
+	}
+	if app.RemoveRoute(MethodPut, "/users/:id") {
+		t.Error("Expected no route to be removed for an unknown method")
+	}
+
+	ctx := createFasthttpRequest(MethodGet, "/users/1")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", StatusMethodNotAllowed, ctx.Response.StatusCode())
+	}
+	if _, err := app.URL("user", "id", 1); err == nil {
+		t.Error("Expected the name of the removed route to be released")
+	}
+
+	app.Get("/users/:id", func(c *Context) {
+		c.Text(StatusOK, "again")
+	}).With(WithName("user"))
+	ctx = createFasthttpRequest(MethodGet, "/users/1")
+	app.serveRequest(ctx)
+	if body := string(ctx.Response.Body()); body != "again" {
+		t.Errorf("Expected the route to be added again, got '%s'", body)
+	}
+}
+
+func TestGroup_RemoveRoute(t *testing.T) {
+	app := NewApp()
+	api := app.Host("api.example.com").Group("/v1")
+	api.Get("/items", func(c *Context) {})
+	app.Get("/v1/items", func(c *Context) {})
+
+	if !api.RemoveRoute(MethodGet, "/items") {
+		t.Fatal("Expected the host route to be removed")
+	}
+	if len(app.Routes()) != 1 || app.Routes()[0].Host != "" {
+		t.Errorf("Expected only the default route to remain, got %+v", app.Routes())
+	}
+}
+
+func TestAddRouteWhileServing(t *testing.T) {
+	app := NewApp()
+	handler := app.RequestHandler()
+	app.Get("/flag", func(c *Context) {
+		c.Text(StatusOK, "on")
+	}).With(WithName("flag"))
+
+	ctx := createFasthttpRequest(MethodGet, "/flag")
+	handler(ctx)
+	if body := string(ctx.Response.Body()); body != "on" {
+		t.Errorf("Expected route added while serving to match, got '%s'", body)
+	}
+	if url, err := app.URL("flag"); err != nil || url != "/flag" {
+		t.Errorf("Expected URL '/flag', got '%s' (%v)", url, err)
+	}
+
+	app.RemoveRoute(MethodGet, "/flag")
+	ctx = createFasthttpRequest(MethodGet, "/flag")
+	handler(ctx)
+	if ctx.Response.StatusCode() != StatusNotFound {
+		t.Errorf("Expected status %d after removal, got %d", StatusNotFound, ctx.Response.StatusCode())
+	}
+}
+
+func TestRouteWithWhileServing(t *testing.T) {
+	app := NewApp()
+	var matched *Route
+	r := app.Get("/users", func(c *Context) {
+		matched = c.Route()
+	})
+	handler := app.RequestHandler()
+
+	updated := r.With(WithMeta("scope", "users:read")).With(WithName("users"))
+	if updated == r {
+		t.Fatal("Expected a copy of the route while serving")
+	}
+	if _, ok := r.Meta("scope"); ok || r.Name() != "" {
+		t.Error("Expected the original route to be left unchanged")
+	}
+
+	handler(createFasthttpRequest(MethodGet, "/users"))
+	if matched != updated {
+		t.Fatal("Expected requests to match the updated route")
+	}
+	if scope, _ := matched.Meta("scope"); scope != "users:read" {
+		t.Errorf("Expected scope 'users:read', got %v", scope)
+	}
+	if url, _ := app.URL("users"); url != "/users" {
+		t.Errorf("Expected URL '/users', got '%s'", url)
+	}
+}
+
+func TestRouteWithReplacedRoute(t *testing.T) {
+	app := NewApp()
+	var matched *Route
+	r := app.Get("/flags", func(c *Context) {
+		matched = c.Route()
+	})
+	handler := app.RequestHandler()
+
+	r.With(WithMeta("enabled", true))
+	updated := r.With(WithMeta("enabled", false)).With(WithName("flags"))
+	handler(createFasthttpRequest(MethodGet, "/flags"))
+	if matched != updated {
+		t.Fatal("Expected requests to match the route updated through the original handle")
+	}
+	if enabled, _ := matched.Meta("enabled"); enabled != false {
+		t.Errorf("Expected the last update to be in use, got %v", enabled)
+	}
+	if url, err := app.URL("flags"); err != nil || url != "/flags" {
+		t.Errorf("Expected URL '/flags', got '%s' and %v", url, err)
+	}
+}
+
+func TestRouteWithRemovedRoute(t *testing.T) {
+	expectPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: Expected a panic for a removed route", name)
+			}
+		}()
+		fn()
+	}
+
+	app := NewApp()
+	removed := app.Get("/removed", func(c *Context) {})
+	app.RemoveRoute(MethodGet, "/removed")
+	expectPanic("before serving", func() { removed.With(WithName("removed")) })
+
+	r := app.Get("/users", func(c *Context) {})
+	app.RequestHandler()
+	updated := r.With(WithName("users"))
+	app.RemoveRoute(MethodGet, "/users")
+	expectPanic("while serving", func() { updated.With(WithName("gone")) })
+	app.Get("/users", func(c *Context) {})
+	expectPanic("added again", func() { r.With(WithName("again")) })
+
+	for _, name := range []string{"removed", "gone", "again"} {
+		if _, err := app.URL(name); err == nil {
+			t.Errorf("Expected no URL for the name '%s' of a removed route", name)
+		}
+	}
+	app.Get("/other", func(c *Context) {}).With(WithName("removed"))
+	if url, err := app.URL("removed"); err != nil || url != "/other" {
+		t.Errorf("Expected the name to be free for other routes, got '%s' and %v", url, err)
+	}
+}
+
+func TestRoutesChangedWhileServing(t *testing.T) {
+	app := NewApp()
+	app.Get("/stable", func(c *Context) {
+		c.Text(StatusOK, "stable")
+	})
+	handler := app.RequestHandler()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				ctx := createFasthttpRequest(MethodGet, "/stable")
+				handler(ctx)
+				if body := string(ctx.Response.Body()); body != "stable" {
+					t.Errorf("Expected 'stable', got '%s'", body)
+					return
+				}
+				handler(createFasthttpRequest(MethodGet, fmt.Sprintf("/flags/%d", j%10)))
+				app.URL("flag")
+			}
+		}()
+	}
+
+	for j := 0; j < 100; j++ {
+		pattern := fmt.Sprintf("/flags/%d", j%10)
+		if j%20 < 10 {
+			app.Get(pattern, func(c *Context) {}).With(WithMeta("flag", j))
+		} else {
+			app.RemoveRoute(MethodGet, pattern)
+		}
+		if j == 50 {
+			app.Host("api.example.com").Get("/flags", func(c *Context) {}).With(WithName("flag"))
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,105 @@
+package lightning
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// URL builds the URL of the route registered under the given name.
+// The params are alternating keys and values: values of the route's parameters are
+// substituted into its pattern, and the others are appended as the query string.
+// Values are formatted with fmt.Sprint and escaped.
+//
+// Routes of a host pattern with parameters, see Host, get a scheme-relative URL such as
+// `//acme.example.com/users`, whose host is filled in with the values of the host parameters.
+func (app *Application) URL(name string, params ...any) (string, error) {
+	t := app.routes()
+	r, ok := t.namedRoutes[name]
+	if !ok {
+		return "", fmt.Errorf("route '%s' not found", name)
+	}
+	return r.url(t.routerOf(r.host), params...)
+}
+
+// url builds the URL of the route from alternating parameter keys and values. The pattern
+// is canonicalized by the router holding the route, which keeps its trailing slash if the
+// router is strict about it.
+func (r *Route) url(rt *router, params ...any) (string, error) {
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("odd number of URL parameters for route '%s'", r.name)
+	}
+
+	var hostLabels []string
+	if strings.Contains(r.host, ":") {
+		hostLabels = strings.Split(r.host, ".")
+	}
+
+	values := make(map[string]string, len(params)/2)
+	query := url.Values{}
+	for i := 0; i < len(params); i += 2 {
+		key, ok := params[i].(string)
+		if !ok {
+			return "", fmt.Errorf("URL parameter key %v of route '%s' is not a string", params[i], r.name)
+		}
+		value := fmt.Sprint(params[i+1])
+		if slices.Contains(r.paramKeys, key) || slices.Contains(hostLabels, ":"+key) {
+			values[key] = value
+		} else {
+			query.Add(key, value)
+		}
+	}
+
+	var b strings.Builder
+	if len(hostLabels) > 0 {
+		b.WriteString("//")
+		for i, label := range hostLabels {
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			if label[0] != ':' {
+				b.WriteString(label)
+				continue
+			}
+			value := values[label[1:]]
+			if value == "" {
+				return "", fmt.Errorf("missing value for host parameter '%s' of route '%s'", label[1:], r.name)
+			}
+			if strings.ContainsAny(value, "./:@?#[]%\\ ") {
+				return "", fmt.Errorf("invalid value '%s' for host parameter '%s' of route '%s'", value, label[1:], r.name)
+			}
+			b.WriteString(value)
+		}
+	}
+	hostLength := b.Len()
+	for _, token := range patternTokens(rt.canonicalPath(r.pattern)) {
+		if token.kind == staticNode {
+			b.WriteString(token.text)
+			continue
+		}
+		key, _ := splitParam(token.text)
+		value, ok := values[key]
+		if !ok || value == "" {
+			return "", fmt.Errorf("missing value for parameter '%s' of route '%s'", key, r.name)
+		}
+		if token.kind == paramNode {
+			b.WriteString(url.PathEscape(value))
+			continue
+		}
+		segments := strings.Split(value, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		b.WriteString(strings.Join(segments, "/"))
+	}
+	if b.Len() == hostLength {
+		b.WriteByte('/')
+	}
+
+	if len(query) > 0 {
+		b.WriteByte('?')
+		b.WriteString(query.Encode())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,230 @@
+package lightning
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplicationURL(t *testing.T) {
+	app := NewApp()
+	app.Get("/", func(c *Context) {}).With(WithName("home"))
+	app.Get("/users/:id<int>", func(c *Context) {}).With(WithName("user"))
+	app.Get("/users/:id/posts/:slug", func(c *Context) {}).With(WithName("post"))
+	app.Get("/files/*path", func(c *Context) {}).With(WithName("file"))
+	app.Get("/assets/:name.:ext", func(c *Context) {}).With(WithName("asset"))
+	app.Get("/repos/*path/raw", func(c *Context) {}).With(WithName("raw"))
+	app.Get("/items/:id::archive", func(c *Context) {}).With(WithName("archive"))
+
+	tests := []struct {
+		name   string
+		params []any
+		want   string
+	}{
+		{"home", nil, "/"},
+		{"user", []any{"id", 42}, "/users/42"},
+		{"user", []any{"id", 42, "page", 2, "sort", "name asc"}, "/users/42?page=2&sort=name+asc"},
+		{"user", []any{"id", 1, "tag", "a", "tag", "b"}, "/users/1?tag=a&tag=b"},
+		{"post", []any{"slug", "hello world/2", "id", 7}, "/users/7/posts/hello%20world%2F2"},
+		{"file", []any{"path", "docs/read me.md"}, "/files/docs/read%20me.md"},
+		{"asset", []any{"name", "app.min", "ext", "js"}, "/assets/app.min.js"},
+		{"raw", []any{"path", "go-labx/lightning"}, "/repos/go-labx/lightning/raw"},
+		{"archive", []any{"id", 3}, "/items/3:archive"},
+	}
+
+	for _, tt := range tests {
+		got, err := app.URL(tt.name, tt.params...)
+		if err != nil {
+			t.Errorf("URL(%q, %v) returned error: %v", tt.name, tt.params, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("URL(%q, %v) = %q, want %q", tt.name, tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestApplicationURLStrictSlash(t *testing.T) {
+	app := NewApp(&Config{TrailingSlash: TrailingSlashStrict})
+	app.Get("/users/", func(c *Context) { c.Text(StatusOK, "users") }).With(WithName("users"))
+	app.Get("/users/:id/", func(c *Context) { c.Text(StatusOK, "user") }).With(WithName("user"))
+	app.Get("/items", func(c *Context) { c.Text(StatusOK, "items") }).With(WithName("items"))
+
+	tests := []struct {
+		name   string
+		params []any
+		want   string
+	}{
+		{"users", nil, "/users/"},
+		{"user", []any{"id", 42}, "/users/42/"},
+		{"items", nil, "/items"},
+	}
+	for _, tt := range tests {
+		got, err := app.URL(tt.name, tt.params...)
+		if err != nil || got != tt.want {
+			t.Errorf("Expected URL '%s' for '%s', got '%s' and %v", tt.want, tt.name, got, err)
+			continue
+		}
+		ctx := createFasthttpRequest(MethodGet, got)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != StatusOK {
+			t.Errorf("Expected status %d for '%s', got %d", StatusOK, got, ctx.Response.StatusCode())
+		}
+	}
+}
+
+func TestApplicationURLErrors(t *testing.T) {
+	app := NewApp()
+	app.Get("/users/:id", func(c *Context) {}).With(WithName("user"))
+
+	tests := []struct {
+		name   string
+		params []any
+	}{
+		{"missing", nil},
+		{"user", nil},
+		{"user", []any{"id"}},
+		{"user", []any{1, 2}},
+		{"user", []any{"id", ""}},
+	}
+
+	for _, tt := range tests {
+		if _, err := app.URL(tt.name, tt.params...); err == nil {
+			t.Errorf("URL(%q, %v) expected error", tt.name, tt.params)
+		}
+	}
+}
+
+func TestApplicationURLHostParams(t *testing.T) {
+	app := NewApp()
+	app.Host(":tenant.example.com").Get("/users/:id", func(c *Context) {}).With(WithName("user"))
+	app.Host(":tenant.example.com").Get("/", func(c *Context) {}).With(WithName("home"))
+	app.Host("api.example.com").Get("/status", func(c *Context) {}).With(WithName("status"))
+
+	tests := []struct {
+		name   string
+		params []any
+		want   string
+	}{
+		{"user", []any{"tenant", "acme", "id", 7, "tab", "posts"}, "//acme.example.com/users/7?tab=posts"},
+		{"home", []any{"tenant", "acme"}, "//acme.example.com/"},
+		{"status", nil, "/status"},
+	}
+	for _, tt := range tests {
+		got, err := app.URL(tt.name, tt.params...)
+		if err != nil || got != tt.want {
+			t.Errorf("URL(%q, %v) = %q, %v, want %q", tt.name, tt.params, got, err, tt.want)
+		}
+	}
+
+	for _, params := range [][]any{{"id", 7}, {"tenant", "evil.com/x", "id", 7}} {
+		if got, err := app.URL("user", params...); err == nil {
+			t.Errorf("Expected an error for %v, got %q", params, got)
+		}
+	}
+}
+
+func TestRouteNameGroup(t *testing.T) {
+	app := NewApp()
+	api := app.Group("/api").Group("/v1")
+	api.Get("/articles/:id", func(c *Context) {}).With(WithName("article"))
+
+	got, err := app.URL("article", "id", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/api/v1/articles/3" {
+		t.Errorf("Expected '/api/v1/articles/3', got '%s'", got)
+	}
+}
+
+func TestRouteNameDuplicate(t *testing.T) {
+	app := NewApp()
+	app.Get("/a", func(c *Context) {}).With(WithName("page"))
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic for duplicate route name")
+		}
+	}()
+	app.Get("/b", func(c *Context) {}).With(WithName("page"))
+}
+
+func TestTryAddRouteDuplicateName(t *testing.T) {
+	app := NewApp()
+	app.Get("/a", func(c *Context) {}).With(WithName("page"))
+
+	r, err := app.TryAddRoute(MethodGet, "/b", []HandlerFunc{func(c *Context) {}}, WithName("page"))
+	if r != nil || err == nil || err.Error() != "route name 'page' is already used by GET /a" {
+		t.Errorf("Expected a name conflict error, got %v and %v", r, err)
+	}
+	ctx := createFasthttpRequest(MethodGet, "/b")
+	app.serveRequest(ctx)
+	if ctx.Response.StatusCode() != StatusNotFound {
+		t.Errorf("Expected the route not to be added, got status %d", ctx.Response.StatusCode())
+	}
+	if _, err := app.TryAddRoute(MethodGet, "/b", []HandlerFunc{func(c *Context) {}}, WithName("other")); err != nil {
+		t.Errorf("Expected the pattern to be free, got %v", err)
+	}
+}
+
+func TestRouteRename(t *testing.T) {
+	app := NewApp()
+	app.Get("/a", func(c *Context) {}).With(WithName("old")).With(WithName("new"))
+
+	if _, err := app.URL("old"); err == nil {
+		t.Error("Expected old name to be released")
+	}
+	if got, _ := app.URL("new"); got != "/a" {
+		t.Errorf("Expected '/a', got '%s'", got)
+	}
+}
+
+func TestContext_URLFor(t *testing.T) {
+	app := NewApp()
+	app.Get("/users/:id", func(c *Context) {}).With(WithName("user"))
+	app.Get("/redirect", func(c *Context) {
+		url, err := c.URLFor("user", "id", 5)
+		if err != nil {
+			c.Text(StatusInternalServerError, err.Error())
+			return
+		}
+		c.Redirect(StatusFound, url)
+	})
+
+	ctx := newTestCtx(MethodGet, "/redirect")
+	app.serveRequest(ctx)
+
+	if location := string(ctx.Response.Header.Peek(HeaderLocation)); !strings.HasSuffix(location, "/users/5") {
+		t.Errorf("Expected redirect to '/users/5', got '%s'", location)
+	}
+}
+
+func TestURLTemplateFunc(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "templates_url")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmplPath := filepath.Join(tmpDir, "link.html")
+	if err := os.WriteFile(tmplPath, []byte(`<a href="{{ url "user" "id" .ID }}">{{ upper .Name }}</a>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewApp()
+	app.SetFuncMap(map[string]any{"upper": strings.ToUpper})
+	app.LoadHTMLGlob(filepath.Join(tmpDir, "*.html"))
+	app.Get("/users/:id", func(c *Context) {}).With(WithName("user"))
+	app.Get("/link", func(c *Context) {
+		c.HTML(StatusOK, "link.html", map[string]any{"ID": 9, "Name": "bob"})
+	})
+
+	ctx := newTestCtx(MethodGet, "/link")
+	app.serveRequest(ctx)
+
+	if body := string(ctx.Response.Body()); body != `<a href="/users/9">BOB</a>` {
+		t.Errorf("Unexpected body: %s", body)
+	}
+}
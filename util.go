@@ -1,8 +1,11 @@
 package lightning
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"path"
+	"runtime"
 	"strings"
 )
 
@@ -13,15 +16,77 @@ func parsePattern(pattern string) []string {
 	for _, part := range parts {
 		if part != "" {
 			result = append(result, part)
-			if part[0] == '*' {
-				break
-			}
 		}
 	}
 
 	return result
 }
 
+// canonicalPath removes empty segments and the trailing slash from a request path or route
+// pattern, so that "/users/" and "//users" match the same routes as "/users".
+// It only allocates when the path is not already in canonical form.
+func canonicalPath(path string) string {
+	if isCanonicalPath(path) {
+		return path
+	}
+	return "/" + strings.Join(parsePattern(path), "/")
+}
+
+// isCanonicalPath reports whether path starts with a slash and has no empty segments
+// and no trailing slash, the root path "/" excepted.
+func isCanonicalPath(path string) bool {
+	if len(path) == 0 || path[0] != '/' {
+		return false
+	}
+	if len(path) == 1 {
+		return true
+	}
+	if path[len(path)-1] == '/' {
+		return false
+	}
+	return !strings.Contains(path, "//")
+}
+
+// isCleanPath reports whether path has no empty segments and no "." or ".." segments,
+// as in "//a/../b".
+func isCleanPath(path string) bool {
+	for _, segment := range strings.Split(strings.TrimSuffix(path, "/"), "/")[1:] {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// cleanPath returns path with its empty, "." and ".." segments removed like path.Clean
+// does, keeping its trailing slash.
+func cleanPath(p string) string {
+	if isCleanPath(p) {
+		return p
+	}
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// callerSource returns the file and line of the first caller outside of this package,
+// which is where a route was registered by the user.
+func callerSource() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/go-labx/lightning.") || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
 // resolveAddress resolves the address to listen on from the given parameters.
 // It checks the PORT environment variable and uses default port if not set.
 func resolveAddress(addr []string) string {
@@ -46,6 +111,16 @@ func defaultNotFound(ctx *Context) {
 	ctx.Text(StatusNotFound, "Not Found")
 }
 
+// defaultMethodNotAllowed is the default handler function for 405 Method Not Allowed error
+func defaultMethodNotAllowed(ctx *Context) {
+	ctx.Text(StatusMethodNotAllowed, "Method Not Allowed")
+}
+
+// defaultOptions is the handler function for automatically answered OPTIONS requests
+func defaultOptions(ctx *Context) {
+	ctx.SetStatus(StatusNoContent)
+}
+
 // defaultInternalServerError is the default handler function for 500 Internal Server Error
 func defaultInternalServerError(ctx *Context) {
 	ctx.Text(StatusInternalServerError, "Internal Server Error")
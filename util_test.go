@@ -59,6 +59,11 @@ func TestParsePattern(t *testing.T) {
 			pattern: "/api/*",
 			want:    []string{"api", "*"},
 		},
+		{
+			name:    "pattern with segments after wildcard",
+			pattern: "/repos/*path/raw",
+			want:    []string{"repos", "*path", "raw"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -69,6 +74,26 @@ func TestParsePattern(t *testing.T) {
 	}
 }
 
+func TestIsCleanPath(t *testing.T) {
+	tests := map[string]bool{
+		"/":            true,
+		"/users":       true,
+		"/users/":      true,
+		"/a/.b/c..":    true,
+		"//users":      false,
+		"/a//b":        false,
+		"/a/./b":       false,
+		"/a/../b":      false,
+		"/a/..":        false,
+		"//a/../users": false,
+	}
+	for path, want := range tests {
+		if got := isCleanPath(path); got != want {
+			t.Errorf("isCleanPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
 func TestResolveAddress(t *testing.T) {
 	os.Setenv("PORT", "1234")
 	defer os.Unsetenv("PORT")
@@ -0,0 +1,305 @@
+package lightning
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/ar"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/it"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/ko"
+	"github.com/go-playground/locales/nl"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/pt_BR"
+	"github.com/go-playground/locales/ru"
+	"github.com/go-playground/locales/tr"
+	"github.com/go-playground/locales/zh"
+	"github.com/go-playground/locales/zh_Hant_TW"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	ar_translations "github.com/go-playground/validator/v10/translations/ar"
+	de_translations "github.com/go-playground/validator/v10/translations/de"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	fr_translations "github.com/go-playground/validator/v10/translations/fr"
+	it_translations "github.com/go-playground/validator/v10/translations/it"
+	ja_translations "github.com/go-playground/validator/v10/translations/ja"
+	ko_translations "github.com/go-playground/validator/v10/translations/ko"
+	nl_translations "github.com/go-playground/validator/v10/translations/nl"
+	pt_translations "github.com/go-playground/validator/v10/translations/pt"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+	ru_translations "github.com/go-playground/validator/v10/translations/ru"
+	tr_translations "github.com/go-playground/validator/v10/translations/tr"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+	zh_tw_translations "github.com/go-playground/validator/v10/translations/zh_tw"
+)
+
+// Validator validates the structs bound by Context.Bind, JSONBody, Handle and the other
+// binding methods. A *validator.Validate satisfies it, so custom tags and struct-level
+// rules can be registered on one and set as the Config's Validator:
+//
+//	v := validator.New()
+//	v.RegisterValidation("sku", validateSKU)
+//	app := lightning.NewApp(&lightning.Config{Validator: v})
+//
+// The validation errors of a *validator.Validate are returned as a *ValidationError with
+// messages translated to the language of the request.
+type Validator interface {
+	Struct(v any) error
+}
+
+// validate is the default Validator. It names fields after their JSON names in messages,
+// and is shared so that it caches struct info.
+var validate = newValidator()
+
+// newValidator returns a *validator.Validate that names fields after their JSON names.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		if name := jsonName(field); name != field.Name {
+			return name
+		}
+		return ""
+	})
+	return v
+}
+
+// FieldError describes a field that failed validation.
+type FieldError struct {
+	// Field is the path of the field with the JSON names of the fields, e.g. "items[0].name".
+	Field string `json:"field"`
+	// Path is the path of the field with the Go names of the fields, e.g. "Items[0].Name".
+	Path string `json:"path"`
+	// Rule is the validation tag the field failed, e.g. "required" or "min".
+	Rule string `json:"rule"`
+	// Param is the parameter of the rule, e.g. "8" for "min=8".
+	Param string `json:"param,omitempty"`
+	// Message describes the error in the language of the request.
+	Message string `json:"message"`
+}
+
+// ValidationError is the error of a struct that failed validation. The binding methods
+// return it wrapped in a 400 Bad Request *HTTPError, and the default ErrorHandler renders
+// its Fields in the "errors" member of the response.
+type ValidationError struct {
+	Fields []FieldError
+	err    error
+}
+
+// Error returns the messages of the fields, separated by semicolons.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the error of the Validator, such as validator.ValidationErrors.
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+// Validate validates v with the Config's Validator if it is a struct or a pointer to one,
+// typically once it is bound with BindQuery and the other binding methods. Errors are
+// returned as a 400 Bad Request *HTTPError wrapping a *ValidationError if they are
+// validator.ValidationErrors, and the error of the Validator otherwise.
+func (c *Context) Validate(v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	validation := defaultValidation
+	if c.App != nil {
+		validation = c.App.getValidation()
+	}
+	err := validation.validator.Struct(rv.Addr().Interface())
+	if err == nil {
+		return nil
+	}
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return NewHTTPError(StatusBadRequest).WithInternal(err)
+	}
+
+	var translator ut.Translator
+	if c.ctx != nil {
+		translator = validation.translator(c.AcceptedLanguages())
+	}
+	validationErr := &ValidationError{Fields: make([]FieldError, len(fieldErrors)), err: err}
+	for i, fe := range fieldErrors {
+		path, field := fieldPaths(rv.Type(), fe.StructNamespace())
+		validationErr.Fields[i] = FieldError{
+			Field:   field,
+			Path:    path,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Translate(translator),
+		}
+	}
+	return NewHTTPError(StatusBadRequest).WithInternal(validationErr)
+}
+
+// validationLocale is a locale the messages of validation errors are translated to.
+type validationLocale struct {
+	locale   func() locales.Translator
+	register func(*validator.Validate, ut.Translator) error
+}
+
+// validationLocales are the locales of validation messages by language tag, in lower case
+// with underscores. English is the fallback for other languages.
+var validationLocales = map[string]validationLocale{
+	"ar":    {ar.New, ar_translations.RegisterDefaultTranslations},
+	"de":    {de.New, de_translations.RegisterDefaultTranslations},
+	"en":    {en.New, en_translations.RegisterDefaultTranslations},
+	"es":    {es.New, es_translations.RegisterDefaultTranslations},
+	"fr":    {fr.New, fr_translations.RegisterDefaultTranslations},
+	"it":    {it.New, it_translations.RegisterDefaultTranslations},
+	"ja":    {ja.New, ja_translations.RegisterDefaultTranslations},
+	"ko":    {ko.New, ko_translations.RegisterDefaultTranslations},
+	"nl":    {nl.New, nl_translations.RegisterDefaultTranslations},
+	"pt":    {pt.New, pt_translations.RegisterDefaultTranslations},
+	"pt_br": {pt_BR.New, pt_BR_translations.RegisterDefaultTranslations},
+	"ru":    {ru.New, ru_translations.RegisterDefaultTranslations},
+	"tr":    {tr.New, tr_translations.RegisterDefaultTranslations},
+	"zh":    {zh.New, zh_translations.RegisterDefaultTranslations},
+	"zh_tw": {zh_Hant_TW.New, zh_tw_translations.RegisterDefaultTranslations},
+}
+
+// validation holds a Validator and the translators of its messages, which are registered
+// on it when the first struct fails validation.
+type validation struct {
+	validator   Validator
+	once        sync.Once
+	translators map[string]ut.Translator
+}
+
+// defaultValidation holds the default Validator, shared by the Applications that use it.
+var defaultValidation = &validation{validator: validate}
+
+// newValidation returns the validation of the Validator.
+func newValidation(v Validator) *validation {
+	if v == nil || v == Validator(validate) {
+		return defaultValidation
+	}
+	return &validation{validator: v}
+}
+
+// getValidation returns the validation of the Config's Validator, which is cached for as
+// long as the Config keeps the same Validator.
+func (app *Application) getValidation() *validation {
+	v := app.Config.Validator
+	if v == nil || v == Validator(validate) {
+		return defaultValidation
+	}
+	if !reflect.TypeOf(v).Comparable() {
+		return newValidation(v)
+	}
+	if cached := app.validation.Load(); cached != nil && cached.validator == v {
+		return cached
+	}
+	validation := newValidation(v)
+	app.validation.Store(validation)
+	return validation
+}
+
+// translator returns the translator for the first of the languages that has one, or the
+// English translator. It returns nil if the Validator is not a *validator.Validate.
+func (v *validation) translator(languages []string) ut.Translator {
+	v.once.Do(v.registerTranslations)
+	for _, language := range languages {
+		tag := strings.ToLower(strings.ReplaceAll(language, "-", "_"))
+		if translator, ok := v.translators[tag]; ok {
+			return translator
+		}
+		base, _, _ := strings.Cut(tag, "_")
+		if translator, ok := v.translators[base]; ok {
+			return translator
+		}
+	}
+	return v.translators["en"]
+}
+
+// registerTranslations registers the default translations of the validationLocales on the
+// Validator if it is a *validator.Validate.
+func (v *validation) registerTranslations() {
+	instance, ok := v.validator.(*validator.Validate)
+	if !ok {
+		return
+	}
+	fallback := en.New()
+	supported := make([]locales.Translator, 0, len(validationLocales))
+	for _, l := range validationLocales {
+		supported = append(supported, l.locale())
+	}
+	uni := ut.New(fallback, supported...)
+
+	v.translators = make(map[string]ut.Translator, len(validationLocales))
+	for tag, l := range validationLocales {
+		translator, _ := uni.GetTranslator(l.locale().Locale())
+		if err := l.register(instance, translator); err != nil {
+			continue
+		}
+		v.translators[tag] = translator
+	}
+}
+
+// fieldPaths returns the path of the field with the given struct namespace in the struct
+// type t, such as "Items[0].Name", and the path with the JSON names of the fields, such as
+// "items[0].name". Embedded structs without a JSON name are flattened, like encoding/json does.
+func fieldPaths(t reflect.Type, namespace string) (string, string) {
+	path := strings.TrimPrefix(namespace, t.Name()+".")
+	segments := strings.Split(path, ".")
+	jsonPath := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		name, index, indexed := strings.Cut(segment, "[")
+		if t == nil || t.Kind() != reflect.Struct {
+			t = nil
+			jsonPath = append(jsonPath, segment)
+			continue
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			t = nil
+			jsonPath = append(jsonPath, segment)
+			continue
+		}
+
+		t = field.Type
+		jsonSegment := jsonName(field)
+		if indexed {
+			jsonSegment += "[" + index
+			for range strings.Count(segment, "[") {
+				for t.Kind() == reflect.Pointer {
+					t = t.Elem()
+				}
+				t = t.Elem()
+			}
+		} else if field.Anonymous && jsonSegment == field.Name {
+			continue
+		}
+		jsonPath = append(jsonPath, jsonSegment)
+	}
+	return path, strings.Join(jsonPath, ".")
+}
+
+// jsonName returns the name of the field in JSON, or the field's name if its json tag
+// does not set one.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
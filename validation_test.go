@@ -0,0 +1,175 @@
+package lightning
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/valyala/fasthttp"
+)
+
+type validationAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type ValidationPaging struct {
+	Limit int `json:"limit" validate:"lte=100"`
+}
+
+type validationOrder struct {
+	ValidationPaging
+	Email     string              `json:"email" validate:"required,email"`
+	Password  string              `json:"password,omitempty" validate:"min=8"`
+	Address   *validationAddress  `json:"address" validate:"required"`
+	Addresses []validationAddress `json:"addresses" validate:"dive"`
+	Note      string              `validate:"max=3"`
+}
+
+func TestValidationError(t *testing.T) {
+	c, ctx := createTestContext(MethodPost, "/orders", []byte(
+		`{"limit":500,"email":"invalid","password":"short","address":{},"addresses":[{"city":"Paris"},{}],"Note":"long"}`))
+	ctx.Request.Header.Set("Accept-Language", "xx, en-US;q=0.8")
+
+	err := c.Bind(&validationOrder{})
+	var httpErr *HTTPError
+	var validationErr *ValidationError
+	if !errors.As(err, &httpErr) || httpErr.Status != StatusBadRequest || !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a 400 HTTPError wrapping a ValidationError, got %v", err)
+	}
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		t.Errorf("Expected the ValidationError to wrap the validator's errors")
+	}
+
+	want := []FieldError{
+		{Field: "limit", Path: "ValidationPaging.Limit", Rule: "lte", Param: "100", Message: "limit must be 100 or less"},
+		{Field: "email", Path: "Email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "password", Path: "Password", Rule: "min", Param: "8", Message: "password must be at least 8 characters in length"},
+		{Field: "address.city", Path: "Address.City", Rule: "required", Message: "city is a required field"},
+		{Field: "addresses[1].city", Path: "Addresses[1].City", Rule: "required", Message: "city is a required field"},
+		{Field: "Note", Path: "Note", Rule: "max", Param: "3", Message: "Note must be a maximum of 3 characters in length"},
+	}
+	if !reflect.DeepEqual(validationErr.Fields, want) {
+		t.Errorf("Expected fields\n%+v\ngot\n%+v", want, validationErr.Fields)
+	}
+	if !strings.HasPrefix(validationErr.Error(), "limit must be 100 or less; email must be") {
+		t.Errorf("Expected the messages joined by semicolons, got '%s'", validationErr.Error())
+	}
+}
+
+func TestValidationErrorTranslations(t *testing.T) {
+	type signup struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	tests := []struct {
+		acceptLanguage string
+		message        string
+	}{
+		{"", "name is a required field"},
+		{"zh-CN,zh;q=0.9", "name为必填字段"},
+		{"zh-TW", "name為必填欄位"},
+		{"de-DE, en;q=0.5", "name ist ein Pflichtfeld"},
+		{"xx", "name is a required field"},
+	}
+	for _, tt := range tests {
+		c, ctx := createTestContext(MethodPost, "/signup", []byte(`{}`))
+		ctx.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+
+		var validationErr *ValidationError
+		if err := c.Bind(&signup{}); !errors.As(err, &validationErr) {
+			t.Errorf("Expected a ValidationError for '%s', got %v", tt.acceptLanguage, err)
+			continue
+		}
+		if message := validationErr.Fields[0].Message; message != tt.message {
+			t.Errorf("Expected message '%s' for '%s', got '%s'", tt.message, tt.acceptLanguage, message)
+		}
+	}
+}
+
+func TestConfigValidator(t *testing.T) {
+	v := validator.New()
+	v.RegisterValidation("sku", func(fl validator.FieldLevel) bool {
+		return strings.HasPrefix(fl.Field().String(), "SKU-")
+	})
+	app := NewApp(&Config{Validator: v})
+	app.Post("/products", Handle(func(c *Context, req struct {
+		SKU string `json:"sku" validate:"sku"`
+	}) (Map, error) {
+		return Map{"sku": req.SKU}, nil
+	}))
+
+	tests := []struct {
+		body   string
+		status int
+		want   string
+	}{
+		{`{"sku":"SKU-1"}`, StatusOK, `{"sku":"SKU-1"}`},
+		{`{"sku":"1"}`, StatusBadRequest, `{"code":400,"errors":[{"field":"sku","path":"SKU","rule":"sku","message":"Key: 'SKU' Error:Field validation for 'SKU' failed on the 'sku' tag"}],"message":"Bad Request"}`},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodPost, "/products")
+		ctx.Request.SetBody([]byte(tt.body))
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("Expected status %d for %s, got %d", tt.status, tt.body, ctx.Response.StatusCode())
+		}
+		if body := string(ctx.Response.Body()); body != tt.want {
+			t.Errorf("Expected body '%s', got '%s'", tt.want, body)
+		}
+	}
+}
+
+type rejectingValidator struct{}
+
+func (rejectingValidator) Struct(v any) error {
+	return errors.New("rejected")
+}
+
+func TestCustomValidator(t *testing.T) {
+	app := NewApp(&Config{Validator: rejectingValidator{}})
+	var err error
+	app.Post("/items", func(c *Context) {
+		err = c.JSONBody(&struct{}{}, true)
+	})
+
+	app.serveRequest(func() *fasthttp.RequestCtx {
+		ctx := createFasthttpRequest(MethodPost, "/items")
+		ctx.Request.SetBody([]byte(`{}`))
+		return ctx
+	}())
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Status != StatusBadRequest || httpErr.Internal.Error() != "rejected" {
+		t.Errorf("Expected a 400 HTTPError wrapping the validator's error, got %v", err)
+	}
+}
+
+func TestConfigValidatorSetAfterNewApp(t *testing.T) {
+	app := NewApp()
+	var err error
+	app.Post("/items", func(c *Context) {
+		err = c.JSONBody(&struct{}{}, true)
+	})
+	request := func() {
+		ctx := createFasthttpRequest(MethodPost, "/items")
+		ctx.Request.SetBody([]byte(`{}`))
+		app.serveRequest(ctx)
+	}
+
+	request()
+	if err != nil {
+		t.Fatalf("Expected no error with the default validator, got %v", err)
+	}
+	app.Config.Validator = rejectingValidator{}
+	request()
+	if err == nil || !strings.Contains(err.Error(), "rejected") {
+		t.Errorf("Expected the validator set after NewApp to be used, got %v", err)
+	}
+	app.Config.Validator = nil
+	request()
+	if err != nil {
+		t.Errorf("Expected the default validator without a Validator, got %v", err)
+	}
+}
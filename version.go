@@ -0,0 +1,204 @@
+package lightning
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// deprecation holds when a route was deprecated and when it is going to be removed.
+type deprecation struct {
+	since  time.Time
+	sunset time.Time
+}
+
+// WithDeprecation returns a RouteOption that marks the route as deprecated since the given
+// time. Its responses carry a Deprecation header with that time, or `true` if it is zero,
+// and unless sunset is zero, a Sunset header (RFC 8594) with the time the route is going to
+// be removed.
+func WithDeprecation(since time.Time, sunset time.Time) RouteOption {
+	return func(r *Route) {
+		r.deprecation = &deprecation{since: since, sunset: sunset}
+	}
+}
+
+// setHeaders sets the Deprecation and Sunset headers of the response.
+func (d *deprecation) setHeaders(c *Context) {
+	if d.since.IsZero() {
+		c.SetHeader(HeaderDeprecation, "true")
+	} else {
+		c.SetHeader(HeaderDeprecation, "@"+strconv.FormatInt(d.since.Unix(), 10))
+	}
+	if !d.sunset.IsZero() {
+		c.SetHeader(HeaderSunset, d.sunset.UTC().Format(http.TimeFormat))
+	}
+}
+
+// Version returns a Group whose routes are only matched for requests asking for the given
+// API version, either with the Accept-Version header or with a vendor media type such as
+// `application/vnd.acme.v2+json` in the Accept header. Requests that do not ask for a version
+// ask for the Config's DefaultVersion. A leading "v" is ignored, so "v2" and "2" are the same
+// version.
+//
+// The routes of the requested version take precedence over the routes registered without
+// a version, which are matched for every version. Requests whose method and path only match
+// the routes of other versions than the requested one, e.g. because it is unknown, or
+// because no version is requested and there is no DefaultVersion, are answered with
+// 406 Not Acceptable, naming those versions. Responses of
+// Applications with versioned routes carry a Vary header naming the headers above.
+func (app *Application) Version(version string) *Group {
+	group := newGroup(app, "")
+	group.version = normalizeVersion(version)
+	return group
+}
+
+// otherVersions returns the sorted API versions of the router, other than the requested
+// version, that have a route for the method and path of the request.
+func (app *Application) otherVersions(rt *router, version string, c *Context) []string {
+	var versions []string
+	var params Params
+	for v, router := range rt.versions {
+		if v != version && app.lookupRoute(router, c.Method, c.Path, &params) != nil {
+			versions = append(versions, v)
+		}
+		params = params[:0]
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// versionNotAcceptable returns a handler answering requests for an API version that does
+// not serve the route, or for no version, with a 406 Not Acceptable error naming the
+// versions that do.
+func versionNotAcceptable(version string, versions []string) HandlerFunc {
+	message := fmt.Sprintf("API version '%s' is not supported, supported versions are %s", version, strings.Join(versions, ", "))
+	if version == "" {
+		message = "an API version is required, supported versions are " + strings.Join(versions, ", ")
+	}
+	return func(c *Context) {
+		c.Error(NewHTTPError(StatusNotAcceptable, message))
+	}
+}
+
+// Version returns a child Group of the Group whose routes are only matched for requests
+// asking for the given API version, see Application.Version.
+func (g *Group) Version(version string) *Group {
+	group := g.Group("")
+	group.version = normalizeVersion(version)
+	return group
+}
+
+// Deprecate marks the routes of the Group and its children as deprecated, see
+// WithDeprecation. Options given to the routes themselves take precedence.
+// It panics once the Application serves requests.
+func (g *Group) Deprecate(since time.Time, sunset time.Time) {
+	g.app.mustNotServe("Group.Deprecate")
+	g.deprecation = &deprecation{since: since, sunset: sunset}
+}
+
+// getDeprecation returns the deprecation of the Group or its closest deprecated ancestor, or nil.
+func (g *Group) getDeprecation() *deprecation {
+	if g.deprecation != nil || g.parent == nil {
+		return g.deprecation
+	}
+	return g.parent.getDeprecation()
+}
+
+// getDeprecation returns the deprecation of the route or of its Group, or nil.
+func (r *Route) getDeprecation() *deprecation {
+	if r.deprecation != nil || r.group == nil {
+		return r.deprecation
+	}
+	return r.group.getDeprecation()
+}
+
+// getVersion returns the API version of the Group or its ancestors, or an empty string.
+func (g *Group) getVersion() string {
+	if g.version != "" || g.parent == nil {
+		return g.version
+	}
+	return g.parent.getVersion()
+}
+
+// Version returns the API version the request asks for, see Application.Version.
+func (c *Context) Version() string {
+	return c.App.requestVersion(c)
+}
+
+// requestVersion returns the API version asked for with the Accept-Version header, or with
+// a vendor media type in the Accept header, or the Config's DefaultVersion.
+func (app *Application) requestVersion(c *Context) string {
+	if version := normalizeVersion(c.Header(HeaderAcceptVersion)); version != "" {
+		return version
+	}
+	if version := mediaTypeVersion(c.Header(HeaderAccept)); version != "" {
+		return version
+	}
+	return normalizeVersion(app.Config.DefaultVersion)
+}
+
+// mediaTypeVersion returns the version of the first vendor media type of the Accept header
+// carrying one, such as "2" for `application/vnd.acme.v2+json`, or an empty string.
+func mediaTypeVersion(accept string) string {
+	for accept != "" {
+		var mediaType string
+		mediaType, accept, _ = strings.Cut(accept, ",")
+		mediaType, _, _ = strings.Cut(mediaType, ";")
+		mediaType, _, _ = strings.Cut(strings.TrimSpace(mediaType), "+")
+
+		vendor, ok := strings.CutPrefix(strings.ToLower(mediaType), "application/vnd.")
+		if !ok {
+			continue
+		}
+		i := strings.LastIndexByte(vendor, '.')
+		if i < 0 {
+			continue
+		}
+		if version := vendor[i+1:]; len(version) > 1 && version[0] == 'v' && isDigit(version[1]) {
+			return version[1:]
+		}
+	}
+	return ""
+}
+
+// normalizeVersion trims the version and removes a leading "v" followed by a digit.
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if len(version) > 1 && (version[0] == 'v' || version[0] == 'V') && isDigit(version[1]) {
+		return version[1:]
+	}
+	return version
+}
+
+// isDigit reports whether b is an ASCII digit.
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// versioned returns the router holding the routes of the given API version, which is the
+// router itself for an empty version, or nil if there are none.
+func (r *router) versioned(version string) *router {
+	if version == "" {
+		return r
+	}
+	return r.versions[version]
+}
+
+// addVersion returns the router holding the routes of the given API version, which is the
+// router itself for an empty version, creating it if needed.
+func (r *router) addVersion(version string) *router {
+	if v := r.versioned(version); v != nil {
+		return v
+	}
+	if r.versions == nil {
+		r.versions = make(map[string]*router)
+	}
+	v := newRouter()
+	v.constraints = r.constraints
+	v.strictSlash = r.strictSlash
+	r.versions[version] = v
+	return v
+}
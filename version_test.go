@@ -0,0 +1,183 @@
+package lightning
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMediaTypeVersion(t *testing.T) {
+	tests := map[string]string{
+		"application/vnd.acme.v2+json":                     "2",
+		"application/vnd.acme.v2.1+json; charset=utf-8":    "",
+		"text/html, application/vnd.acme.v3+json;q=0.9":    "3",
+		"application/vnd.acme.api.V10":                     "10",
+		"application/vnd.acme+json":                        "",
+		"application/json":                                 "",
+		"application/vnd.acme.version+json, */*":           "",
+		"application/vnd.acme.vx+json, application/vnd.v4": "",
+		"": "",
+	}
+	for accept, want := range tests {
+		if got := mediaTypeVersion(accept); got != want {
+			t.Errorf("Expected version '%s' for '%s', got '%s'", want, accept, got)
+		}
+	}
+}
+
+func newVersionedApp() *Application {
+	app := NewApp(&Config{DefaultVersion: "1"})
+	app.Get("/health", func(c *Context) {
+		c.Text(StatusOK, "ok")
+	})
+	app.Version("1").Get("/users", func(c *Context) {
+		c.Text(StatusOK, "users v1")
+	})
+	v2 := app.Version("v2")
+	v2.Get("/users", func(c *Context) {
+		c.Text(StatusOK, "users v"+c.Version())
+	})
+	v2.Group("/admin").Post("/users", func(c *Context) {})
+	return app
+}
+
+func TestVersion(t *testing.T) {
+	app := newVersionedApp()
+
+	tests := []struct {
+		path    string
+		headers map[string]string
+		status  int
+		body    string
+	}{
+		{"/users", nil, StatusOK, "users v1"},
+		{"/users", map[string]string{HeaderAcceptVersion: "2"}, StatusOK, "users v2"},
+		{"/users", map[string]string{HeaderAcceptVersion: "v2"}, StatusOK, "users v2"},
+		{"/users", map[string]string{HeaderAccept: "application/vnd.acme.v2+json"}, StatusOK, "users v2"},
+		{"/users", map[string]string{HeaderAcceptVersion: "1", HeaderAccept: "application/vnd.acme.v2+json"}, StatusOK, "users v1"},
+		{"/users", map[string]string{HeaderAcceptVersion: "3"}, StatusNotAcceptable,
+			`{"code":406,"message":"API version '3' is not supported, supported versions are 1, 2"}`},
+		{"/health", map[string]string{HeaderAcceptVersion: "2"}, StatusOK, "ok"},
+		{"/admin/users", map[string]string{HeaderAcceptVersion: "2"}, StatusMethodNotAllowed, "Method Not Allowed"},
+		{"/admin/users", nil, StatusNotFound, "Not Found"},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodGet, tt.path)
+		for key, value := range tt.headers {
+			ctx.Request.Header.Set(key, value)
+		}
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("Expected status %d for %v, got %d", tt.status, tt.headers, ctx.Response.StatusCode())
+		}
+		if body := string(ctx.Response.Body()); body != tt.body {
+			t.Errorf("Expected body '%s' for %v, got '%s'", tt.body, tt.headers, body)
+		}
+		if vary := string(ctx.Response.Header.Peek(HeaderVary)); vary != "Accept-Version, Accept" {
+			t.Errorf("Expected Vary header, got '%s'", vary)
+		}
+	}
+
+	routes := app.Routes()
+	if len(routes) != 4 || routes[2].Version != "1" || routes[3].Version != "2" {
+		t.Errorf("Expected versioned routes, got %+v", routes)
+	}
+
+	if app.RemoveRoute(MethodGet, "/users") {
+		t.Error("Expected versioned routes not to be removed without their version")
+	}
+	if !app.Version("2").RemoveRoute(MethodGet, "/users") {
+		t.Error("Expected the versioned route to be removed")
+	}
+}
+
+func TestVersionNotServingRoute(t *testing.T) {
+	app := NewApp()
+	app.Post("/items", func(c *Context) {})
+	app.Version("1").Get("/items", func(c *Context) {})
+	app.Version("2").Get("/items", func(c *Context) {})
+	app.Version("2").Get("/reports", func(c *Context) {})
+
+	tests := []struct {
+		method  string
+		path    string
+		version string
+		status  int
+		body    string
+		allow   string
+	}{
+		{MethodGet, "/items", "3", StatusNotAcceptable,
+			`{"code":406,"message":"API version '3' is not supported, supported versions are 1, 2"}`, ""},
+		{MethodGet, "/items", "", StatusNotAcceptable,
+			`{"code":406,"message":"an API version is required, supported versions are 1, 2"}`, ""},
+		{MethodGet, "/reports", "1", StatusNotAcceptable,
+			`{"code":406,"message":"API version '1' is not supported, supported versions are 2"}`, ""},
+		{MethodDelete, "/items", "3", StatusMethodNotAllowed, "Method Not Allowed", "OPTIONS, POST"},
+		{MethodGet, "/missing", "3", StatusNotFound, "Not Found", ""},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(tt.method, tt.path)
+		ctx.Request.Header.Set(HeaderAcceptVersion, tt.version)
+		app.serveRequest(ctx)
+		if ctx.Response.StatusCode() != tt.status {
+			t.Errorf("Expected status %d for %s %s v%s, got %d", tt.status, tt.method, tt.path, tt.version, ctx.Response.StatusCode())
+		}
+		if body := string(ctx.Response.Body()); body != tt.body {
+			t.Errorf("Expected body '%s' for %s %s v%s, got '%s'", tt.body, tt.method, tt.path, tt.version, body)
+		}
+		if allow := string(ctx.Response.Header.Peek(HeaderAllow)); allow != tt.allow {
+			t.Errorf("Expected Allow '%s' for %s %s v%s, got '%s'", tt.allow, tt.method, tt.path, tt.version, allow)
+		}
+	}
+}
+
+func TestVersionWithoutVersionedRoutes(t *testing.T) {
+	app := NewApp()
+	app.Get("/users", func(c *Context) {
+		c.Text(StatusOK, "["+c.Version()+"]")
+	})
+
+	ctx := createFasthttpRequest(MethodGet, "/users")
+	ctx.Request.Header.Set(HeaderAcceptVersion, "2")
+	app.serveRequest(ctx)
+	if body := string(ctx.Response.Body()); body != "[2]" {
+		t.Errorf("Expected body '[2]', got '%s'", body)
+	}
+	if len(ctx.Response.Header.Peek(HeaderVary)) != 0 {
+		t.Error("Expected no Vary header without versioned routes")
+	}
+}
+
+func TestDeprecation(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 12, 31, 23, 59, 59, 0, time.FixedZone("CET", 3600))
+
+	app := NewApp()
+	v1 := app.Version("1")
+	v1.Deprecate(since, sunset)
+	v1.Get("/users", func(c *Context) {})
+	v1.Get("/legacy", func(c *Context) {}).With(WithDeprecation(time.Time{}, time.Time{}))
+	app.Get("/reports", func(c *Context) {}, func(c *Context) {}).With(WithDeprecation(since, time.Time{}))
+	app.Get("/current", func(c *Context) {})
+
+	tests := []struct {
+		path        string
+		deprecation string
+		sunset      string
+	}{
+		{"/users", "@1767225600", "Thu, 31 Dec 2026 22:59:59 GMT"},
+		{"/legacy", "true", ""},
+		{"/reports", "@1767225600", ""},
+		{"/current", "", ""},
+	}
+	for _, tt := range tests {
+		ctx := createFasthttpRequest(MethodGet, tt.path)
+		ctx.Request.Header.Set(HeaderAcceptVersion, "1")
+		app.serveRequest(ctx)
+		if got := string(ctx.Response.Header.Peek(HeaderDeprecation)); got != tt.deprecation {
+			t.Errorf("Expected Deprecation '%s' for '%s', got '%s'", tt.deprecation, tt.path, got)
+		}
+		if got := string(ctx.Response.Header.Peek(HeaderSunset)); got != tt.sunset {
+			t.Errorf("Expected Sunset '%s' for '%s', got '%s'", tt.sunset, tt.path, got)
+		}
+	}
+}